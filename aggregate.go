@@ -0,0 +1,92 @@
+package attribution
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// AggregateContributions collapses contributions with identical touchpoint sequences into a single contribution,
+// summing their values, journeys and conversions.
+// Contributions are returned in the order their sequences first occurred. Since all models are linear in the
+// contributions, aggregating first doesn't change their results but speeds them up considerably. Timestamps can't be
+// combined, so contributions with timestamps are kept as they are to preserve their time-decay values.
+func AggregateContributions(allContributions []Contribution) []Contribution {
+	indices := make(map[string]int)
+	var aggregated []Contribution
+
+	for _, contribution := range allContributions {
+		if len(contribution.Timestamps) > 0 && contribution.HasTimestamps() {
+			kept := Contribution{
+				Touchpoints:    append(Touchpoints(nil), contribution.Touchpoints...),
+				Journeys:       contribution.Journeys,
+				Conversions:    contribution.Conversions,
+				Timestamps:     append([]time.Time(nil), contribution.Timestamps...),
+				ConversionTime: contribution.ConversionTime,
+			}
+			kept.Value.Set(&contribution.Value)
+			aggregated = append(aggregated, kept)
+			continue
+		}
+		key := getTouchpointsKey(contribution.Touchpoints)
+		index, found := indices[key]
+		if !found {
+			index = len(aggregated)
+			indices[key] = index
+			aggregated = append(aggregated, Contribution{
+				Touchpoints: append(Touchpoints(nil), contribution.Touchpoints...),
+			})
+		}
+		aggregated[index].Value.Add(&aggregated[index].Value, &contribution.Value)
+		aggregated[index].Journeys += contribution.GetJourneys()
+		aggregated[index].Conversions += contribution.GetConversions()
+	}
+
+	return aggregated
+}
+
+// AggregateContributionSets collapses contributions with identical touchpoint sets into a single contribution, summing
+// their values, journeys and conversions.
+// Contributions are returned in the order their sets first occurred.
+func AggregateContributionSets(allContributions []ContributionSet) []ContributionSet {
+	indices := make(map[string]int)
+	var aggregated []ContributionSet
+
+	for _, contribution := range allContributions {
+		key := getCoalitionKey(contribution.Touchpoints)
+		index, found := indices[key]
+		if !found {
+			index = len(aggregated)
+			indices[key] = index
+			touchpointSet := make(map[Touchpoint]struct{}, len(contribution.Touchpoints))
+			for touchpoint := range contribution.Touchpoints {
+				touchpointSet[touchpoint] = struct{}{}
+			}
+			aggregated = append(aggregated, ContributionSet{Touchpoints: touchpointSet})
+		}
+		aggregated[index].Value.Add(&aggregated[index].Value, &contribution.Value)
+		aggregated[index].Journeys += contribution.GetJourneys()
+		aggregated[index].Conversions += contribution.GetConversions()
+	}
+
+	return aggregated
+}
+
+// getTouchpointsKey returns a string uniquely identifying the given sequence of touchpoints.
+func getTouchpointsKey(touchpoints Touchpoints) string {
+	names := make([]string, len(touchpoints))
+	for index, touchpoint := range touchpoints {
+		names[index] = touchpoint.Name
+	}
+	return strings.Join(names, "\x00")
+}
+
+// getCoalitionKey returns a string uniquely identifying the given set of touchpoints.
+func getCoalitionKey(coalition map[Touchpoint]struct{}) string {
+	touchpoints := make(Touchpoints, 0, len(coalition))
+	for touchpoint := range coalition {
+		touchpoints = append(touchpoints, touchpoint)
+	}
+	sort.Sort(touchpoints)
+	return getTouchpointsKey(touchpoints)
+}
@@ -0,0 +1,132 @@
+package attribution
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func ExampleAggregateContributions() {
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Touchpoint 1"},
+				Touchpoint{"Touchpoint 2"},
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Touchpoint 2"},
+				Touchpoint{"Touchpoint 1"},
+			},
+			Value: *new(big.Float).SetFloat64(200.),
+		},
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Touchpoint 1"},
+				Touchpoint{"Touchpoint 2"},
+			},
+			Value:       *new(big.Float).SetFloat64(300.),
+			Journeys:    10,
+			Conversions: 0,
+		},
+	}
+
+	for _, contribution := range AggregateContributions(contributions) {
+		fmt.Println(contribution, contribution.Journeys, contribution.Conversions)
+	}
+	for _, contribution := range AggregateContributionSets(getContributionSets(contributions)) {
+		fmt.Println(contribution.Value.String(), contribution.Journeys, contribution.Conversions)
+	}
+	// Output:
+	// {[{Touchpoint 1} {Touchpoint 2}] 400} 11 1
+	// {[{Touchpoint 2} {Touchpoint 1}] 200} 1 1
+	// 600 12 2
+}
+
+func TestAggregateContributions(t *testing.T) {
+	contributions := append(contributionFixture(), contributionFixture()...)
+	aggregated := AggregateContributions(contributions)
+
+	if len(aggregated) != len(contributionFixture())-4 {
+		// the fixture contains five contributions without touchpoints
+		t.Errorf("got %d contributions want %d", len(aggregated), len(contributionFixture())-4)
+	}
+
+	for _, model := range []Model{LinearModel{}, RepeatedLinearModel{}, ShapleyModel{}, MarkovModel{Order: 2}} {
+		want, err := model.Attribute(contributions)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", model.Name(), err)
+		}
+		got, err := model.Attribute(aggregated)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", model.Name(), err)
+		}
+		for touchpoint, wantValue := range want {
+			gotValue := got[touchpoint]
+			gotFloat, _ := gotValue.Float64()
+			wantFloat, _ := wantValue.Float64()
+			if math.Abs(gotFloat-wantFloat) > 1e-6 {
+				t.Errorf("%s: %s: got %f want %f", model.Name(), touchpoint, gotFloat, wantFloat)
+			}
+		}
+	}
+}
+
+func TestAggregateContributionsTimestamps(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	timestamped := contributionFixture()
+	for index := range timestamped {
+		for position := range timestamped[index].Touchpoints {
+			timestamped[index].Timestamps = append(timestamped[index].Timestamps, start.Add(time.Duration(position)*time.Hour))
+		}
+	}
+	contributions := append(timestamped, contributionFixture()...)
+	aggregated := AggregateContributions(contributions)
+
+	// timestamped contributions are kept, the ones without touchpoints or timestamps are aggregated
+	if len(aggregated) != 2*len(contributionFixture())-9 {
+		t.Errorf("got %d contributions want %d", len(aggregated), 2*len(contributionFixture())-9)
+	}
+
+	model := TimeDecayModel{HalfLife: time.Hour}
+	want, err := model.Attribute(contributions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := model.Attribute(aggregated)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for touchpoint, wantValue := range want {
+		gotValue := got[touchpoint]
+		gotFloat, _ := gotValue.Float64()
+		wantFloat, _ := wantValue.Float64()
+		if math.Abs(gotFloat-wantFloat) > 1e-6 {
+			t.Errorf("%s: got %f want %f", touchpoint, gotFloat, wantFloat)
+		}
+	}
+}
+
+func TestAggregateContributionSets(t *testing.T) {
+	contributions := append(contributionSetFixture(), contributionSetFixture()...)
+	aggregated := AggregateContributionSets(contributions)
+
+	totalValue := GetTotalValue(contributions)
+	aggregatedValue := GetTotalValue(aggregated)
+	if totalValue.Cmp(&aggregatedValue) != 0 {
+		t.Errorf("got %s want %s", aggregatedValue.String(), totalValue.String())
+	}
+
+	want := GetShapleyValues(contributions)
+	got := GetShapleyValues(aggregated)
+	for touchpoint, wantValue := range want {
+		gotValue := got[touchpoint]
+		if gotValue.Cmp(&wantValue) != 0 {
+			t.Errorf("%s: got %s want %s", touchpoint, gotValue.String(), wantValue.String())
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package attribution
+
+import (
+	"math/big"
+)
+
+// GetBanzhafValue returns the Banzhaf value of a given touchpoint over all provided contributions, i.e. its marginal
+// contribution averaged over all coalitions of the other touchpoints, using the characteristic function of
+// GetCoalitionValue.
+// A touchpoint that doesn't occur in any contribution has a Banzhaf value of zero.
+// In contrast to Shapley values, Banzhaf values generally don't add up to the total value; see
+// GetNormalizedBanzhafValues.
+func GetBanzhafValue(touchpoint Touchpoint, allContributions []ContributionSet) big.Float {
+	return GetBanzhafValues(allContributions)[touchpoint]
+}
+
+// GetBanzhafValueChecked is like GetBanzhafValue, but returns an error for empty or non-finite input and for
+// touchpoints that don't occur in any contribution.
+func GetBanzhafValueChecked(touchpoint Touchpoint, allContributions []ContributionSet) (big.Float, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateSetTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	return GetBanzhafValue(touchpoint, allContributions), nil
+}
+
+// GetBanzhafValues returns the Banzhaf values of all touchpoints encountered in the provided contributions.
+// The values are derived from the Harsanyi dividends of the game: every member of a coalition with n touchpoints
+// receives 1 / 2^(n-1) of its dividend, so the runtime is linear in the total length of all contributions.
+func GetBanzhafValues(allContributions []ContributionSet) AttributionResult {
+	banzhafValues := make(AttributionResult)
+	for _, touchpoint := range GetAllTouchpoints(allContributions) {
+		banzhafValues[touchpoint] = big.Float{}
+	}
+
+	for _, dividend := range GetHarsanyiDividends(allContributions) {
+		if len(dividend.Coalition) == 0 {
+			continue
+		}
+		share := new(big.Float).SetMantExp(&dividend.Value, 1-len(dividend.Coalition))
+		for touchpoint := range dividend.Coalition {
+			banzhafValue := banzhafValues[touchpoint]
+			banzhafValue.Add(&banzhafValue, share)
+			banzhafValues[touchpoint] = banzhafValue
+		}
+	}
+
+	return banzhafValues
+}
+
+// GetBanzhafValuesChecked is like GetBanzhafValues, but returns an error for empty or non-finite input.
+func GetBanzhafValuesChecked(allContributions []ContributionSet) (AttributionResult, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return nil, err
+	}
+	return GetBanzhafValues(allContributions), nil
+}
+
+// GetNormalizedBanzhafValues returns the Banzhaf values of all touchpoints encountered in the provided contributions,
+// scaled proportionally so that they add up to the total value of all contributions with at least one touchpoint, just
+// like Shapley values do.
+// If the Banzhaf values add up to zero, they are returned unscaled.
+func GetNormalizedBanzhafValues(allContributions []ContributionSet) AttributionResult {
+	banzhafValues := GetBanzhafValues(allContributions)
+
+	banzhafTotal := banzhafValues.GetTotal()
+	if banzhafTotal.Sign() == 0 {
+		return banzhafValues
+	}
+	total := new(big.Float)
+	for _, contribution := range allContributions {
+		if len(contribution.Touchpoints) > 0 {
+			total.Add(total, &contribution.Value)
+		}
+	}
+
+	normalizedValues := make(AttributionResult, len(banzhafValues))
+	for touchpoint, banzhafValue := range banzhafValues {
+		normalizedValue := new(big.Float).Mul(&banzhafValue, total)
+		normalizedValue.Quo(normalizedValue, &banzhafTotal)
+		normalizedValues[touchpoint] = *normalizedValue
+	}
+
+	return normalizedValues
+}
+
+// GetNormalizedBanzhafValuesChecked is like GetNormalizedBanzhafValues, but returns an error for empty or non-finite
+// input.
+func GetNormalizedBanzhafValuesChecked(allContributions []ContributionSet) (AttributionResult, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return nil, err
+	}
+	return GetNormalizedBanzhafValues(allContributions), nil
+}
@@ -0,0 +1,131 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func ExampleGetBanzhafValues() {
+	contributions := []ContributionSet{
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 1"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 1"}: struct{}{},
+				Touchpoint{"Touchpoint 2"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(200.),
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 1"}: struct{}{},
+				Touchpoint{"Touchpoint 3"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(300.),
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 1"}: struct{}{},
+				Touchpoint{"Touchpoint 2"}: struct{}{},
+				Touchpoint{"Touchpoint 3"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(400.),
+		},
+	}
+	banzhafValues := GetBanzhafValues(contributions)
+	normalizedValues := GetNormalizedBanzhafValues(contributions)
+
+	for _, touchpoint := range GetAllTouchpoints(contributions) {
+		banzhafValue := banzhafValues[touchpoint]
+		normalizedValue := normalizedValues[touchpoint]
+		fmt.Println(touchpoint.Name, banzhafValue.Text('f', 2), normalizedValue.Text('f', 2))
+	}
+	// Output:
+	// Touchpoint 1 450.00 500.00
+	// Touchpoint 2 200.00 222.22
+	// Touchpoint 3 250.00 277.78
+}
+
+func TestGetBanzhafValues(t *testing.T) {
+	contributions := contributionSetFixture()
+	banzhafValues := GetBanzhafValues(contributions)
+	// average the marginal contributions over all coalitions without taking any shortcut
+	table := newCoalitionTable(contributions)
+	weights := getBanzhafWeights(len(table.touchpoints))
+
+	for index, touchpoint := range table.touchpoints {
+		banzhafValue := banzhafValues[touchpoint]
+		expectedValue := table.getSemivalue(uint(index), weights)
+
+		got, _ := banzhafValue.Float64()
+		want, _ := expectedValue.Float64()
+
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: got %f want %f", touchpoint, got, want)
+		}
+	}
+
+	if value := GetBanzhafValue(Touchpoint{"unknown"}, contributions); value.Sign() != 0 {
+		t.Errorf("got %s want 0", value.String())
+	}
+}
+
+func TestBanzhafModelManyTouchpoints(t *testing.T) {
+	// a table of all coalitions of this many touchpoints couldn't even be allocated
+	var contributions []Contribution
+	for index := 0; index < 70; index++ {
+		contributions = append(contributions, Contribution{
+			Touchpoints: Touchpoints{Touchpoint{fmt.Sprintf("channel %d", index)}, Touchpoint{fmt.Sprintf("channel %d", (index+1)%70)}},
+			Value:       *big.NewFloat(100),
+		})
+	}
+
+	banzhafValues, err := BanzhafModel{}.Attribute(contributions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(banzhafValues) != 70 {
+		t.Errorf("got %d values want 70", len(banzhafValues))
+	}
+	// every touchpoint receives half of both contributions it takes part in
+	for touchpoint, banzhafValue := range banzhafValues {
+		if got, _ := banzhafValue.Float64(); got != 100 {
+			t.Errorf("%s: got %f want 100", touchpoint.Name, got)
+		}
+	}
+}
+
+func TestGetNormalizedBanzhafValuesEfficiency(t *testing.T) {
+	var contributions []ContributionSet
+	for _, contribution := range contributionSetFixture() {
+		// contributions without touchpoints can't be attributed to any touchpoint
+		if len(contribution.Touchpoints) > 0 {
+			contributions = append(contributions, contribution)
+		}
+	}
+
+	if err := GetNormalizedBanzhafValues(contributions).CheckEfficiency(contributions, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetBanzhafValuesChecked(t *testing.T) {
+	contributions := contributionSetFixture()
+
+	if _, err := GetBanzhafValuesChecked(nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("got %v want ErrEmptyInput", err)
+	}
+	if _, err := GetNormalizedBanzhafValuesChecked(nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("got %v want ErrEmptyInput", err)
+	}
+	if _, err := GetBanzhafValueChecked(Touchpoint{"unknown"}, contributions); !errors.Is(err, ErrUnknownTouchpoint) {
+		t.Errorf("got %v want ErrUnknownTouchpoint", err)
+	}
+}
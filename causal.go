@@ -0,0 +1,210 @@
+package attribution
+
+import (
+	"math/big"
+)
+
+// ConversionProbabilityValue is the characteristic function under which a coalition achieves the probability that a
+// journey exposed to exactly its touchpoints converts, estimated by the share of converting journeys among all
+// journeys with that touchpoint set.
+// Coalitions that no journey was exposed to are estimated by pooling all journeys whose touchpoint sets they contain;
+// if there are none, their probability is zero.
+type ConversionProbabilityValue []ContributionSet
+
+// GetTouchpoints returns all touchpoints encountered in the contributions.
+func (function ConversionProbabilityValue) GetTouchpoints() Touchpoints {
+	return GetAllTouchpoints(function)
+}
+
+// GetValue returns the estimated conversion probability of journeys exposed to exactly the touchpoints of the
+// coalition. Every evaluation takes a single pass over the contributions.
+func (function ConversionProbabilityValue) GetValue(coalition map[Touchpoint]struct{}) big.Float {
+	var journeys, conversions, pooledJourneys, pooledConversions big.Float
+	for _, contribution := range function {
+		if !isSubset(contribution.Touchpoints, coalition) {
+			continue
+		}
+		contributionJourneys := new(big.Float).SetInt64(contribution.GetJourneys())
+		contributionConversions := new(big.Float).SetInt64(contribution.GetConversions())
+		pooledJourneys.Add(&pooledJourneys, contributionJourneys)
+		pooledConversions.Add(&pooledConversions, contributionConversions)
+		if len(contribution.Touchpoints) == len(coalition) {
+			journeys.Add(&journeys, contributionJourneys)
+			conversions.Add(&conversions, contributionConversions)
+		}
+	}
+
+	var probability big.Float
+	switch {
+	case journeys.Sign() > 0:
+		probability.Quo(&conversions, &journeys)
+	case pooledJourneys.Sign() > 0:
+		probability.Quo(&pooledConversions, &pooledJourneys)
+	}
+	return probability
+}
+
+// isSubset reports whether every touchpoint of the first set is contained in the second one.
+func isSubset(touchpoints map[Touchpoint]struct{}, coalition map[Touchpoint]struct{}) bool {
+	if len(touchpoints) > len(coalition) {
+		return false
+	}
+	for touchpoint := range touchpoints {
+		if _, found := coalition[touchpoint]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// A conversionCount holds the number of journeys and conversions of all contributions with the same touchpoint set.
+type conversionCount struct {
+	journeys    big.Float
+	conversions big.Float
+}
+
+// getConversionCounts returns the conversion counts of all distinct touchpoint sets of the contributions, keyed by
+// getCoalitionKey.
+func (function ConversionProbabilityValue) getConversionCounts() map[string]*conversionCount {
+	counts := make(map[string]*conversionCount)
+	for _, contribution := range function {
+		key := getCoalitionKey(contribution.Touchpoints)
+		count, found := counts[key]
+		if !found {
+			count = new(conversionCount)
+			counts[key] = count
+		}
+		count.journeys.Add(&count.journeys, new(big.Float).SetInt64(contribution.GetJourneys()))
+		count.conversions.Add(&count.conversions, new(big.Float).SetInt64(contribution.GetConversions()))
+	}
+	return counts
+}
+
+// fillConversionProbabilities sets the value of every coalition of the table to its estimated conversion probability,
+// given the conversion counts of getConversionCounts.
+func fillConversionProbabilities(table coalitionTable, counts map[string]*conversionCount) {
+	journeys := make([]big.Float, len(table.values))
+	conversions := make([]big.Float, len(table.values))
+	observed := make([]bool, len(table.values))
+	for mask := range table.values {
+		count, found := counts[getCoalitionKey(table.getCoalition(uint(mask)))]
+		if !found {
+			continue
+		}
+		journeys[mask].Set(&count.journeys)
+		conversions[mask].Set(&count.conversions)
+		observed[mask] = true
+	}
+
+	pooledJourneys := coalitionTable{touchpoints: table.touchpoints, values: make([]big.Float, len(table.values))}
+	pooledConversions := coalitionTable{touchpoints: table.touchpoints, values: make([]big.Float, len(table.values))}
+	for mask := range table.values {
+		pooledJourneys.values[mask].Set(&journeys[mask])
+		pooledConversions.values[mask].Set(&conversions[mask])
+	}
+	pooledJourneys.zetaTransform()
+	pooledConversions.zetaTransform()
+
+	for mask := range table.values {
+		switch {
+		case observed[mask] && journeys[mask].Sign() > 0:
+			table.values[mask].Quo(&conversions[mask], &journeys[mask])
+		case pooledJourneys.values[mask].Sign() > 0:
+			table.values[mask].Quo(&pooledConversions.values[mask], &pooledJourneys.values[mask])
+		default:
+			table.values[mask].SetInt64(0)
+		}
+	}
+}
+
+// GetCausalValue returns the causally motivated value of a given touchpoint over all provided contributions, as
+// described for GetCausalValues.
+// A touchpoint that doesn't occur in any contribution has a value of zero.
+func GetCausalValue(touchpoint Touchpoint, allContributions []ContributionSet) big.Float {
+	return GetCausalValues(allContributions)[touchpoint]
+}
+
+// GetCausalValueChecked is like GetCausalValue, but returns an error for empty or non-finite input, for invalid counts
+// and for touchpoints that don't occur in any contribution.
+func GetCausalValueChecked(touchpoint Touchpoint, allContributions []ContributionSet) (big.Float, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateSetTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	return GetCausalValue(touchpoint, allContributions), nil
+}
+
+// GetCausalValues returns the causally motivated values of all touchpoints encountered in the provided contributions,
+// following Dalessandro et al., "Causally Motivated Attribution for Online Advertising" (2012).
+// For every distinct touchpoint set, each of its touchpoints is credited with its Shapley value in the game in which
+// a coalition achieves its conversion probability (see ConversionProbabilityValue), i.e. with the change in conversion
+// probability from adding the touchpoint, averaged over all orders of exposure. The value of all contributions with
+// that set is split in proportion to these credits. Touchpoints with negative credits, which lower the conversion
+// probability, receive nothing; if no credit is positive, the value is split evenly. Thus, non-converting journeys
+// inform the split, and the values add up to the total value of all contributions with at least one touchpoint.
+// The runtime grows exponentially in the number of distinct touchpoints per contribution, but not in the total number
+// of touchpoints.
+func GetCausalValues(allContributions []ContributionSet) AttributionResult {
+	counts := ConversionProbabilityValue(allContributions).getConversionCounts()
+	causalValues := make(AttributionResult)
+	for _, touchpoint := range GetAllTouchpoints(allContributions) {
+		causalValues[touchpoint] = big.Float{}
+	}
+
+	for _, contribution := range AggregateContributionSets(allContributions) {
+		if len(contribution.Touchpoints) == 0 || contribution.Value.Sign() == 0 {
+			continue
+		}
+
+		table := newEmptyCoalitionTable(GetAllTouchpoints([]ContributionSet{contribution}))
+		fillConversionProbabilities(table, counts)
+		weights := getShapleyWeights(len(table.touchpoints))
+		credits := make([]big.Float, len(table.touchpoints))
+		for index := range table.touchpoints {
+			credits[index] = table.getSemivalue(uint(index), weights)
+		}
+
+		addProportionalValues(causalValues, &contribution.Value, table.touchpoints, credits)
+	}
+
+	return causalValues
+}
+
+// GetCausalValuesChecked is like GetCausalValues, but returns an error for empty or non-finite input and for invalid
+// counts.
+func GetCausalValuesChecked(allContributions []ContributionSet) (AttributionResult, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return nil, err
+	}
+	return GetCausalValues(allContributions), nil
+}
+
+// addProportionalValues splits the given value among the given touchpoints in proportion to their positive credits,
+// or evenly if none of the credits is positive, and adds the shares to the values of the touchpoints.
+// Negative credits are treated as zero, since touchpoints lowering the conversion probability would otherwise receive
+// negative shares, which grow without bound as the credits cancel out.
+func addProportionalValues(values AttributionResult, value *big.Float, touchpoints Touchpoints, credits []big.Float) {
+	totalCredit := new(big.Float)
+	for index := range credits {
+		if credits[index].Sign() > 0 {
+			totalCredit.Add(totalCredit, &credits[index])
+		}
+	}
+
+	for index, touchpoint := range touchpoints {
+		share := new(big.Float)
+		if totalCredit.Sign() > 0 {
+			if credits[index].Sign() > 0 {
+				share.Mul(value, &credits[index])
+				share.Quo(share, totalCredit)
+			}
+		} else {
+			share.Quo(value, new(big.Float).SetInt64(int64(len(touchpoints))))
+		}
+		touchpointValue := values[touchpoint]
+		touchpointValue.Add(&touchpointValue, share)
+		values[touchpoint] = touchpointValue
+	}
+}
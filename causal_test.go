@@ -0,0 +1,171 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func causalFixture() []ContributionSet {
+	return []ContributionSet{
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{Touchpoint{"Search"}: struct{}{}},
+			Value:       *new(big.Float).SetFloat64(1000.),
+			Journeys:    100,
+			Conversions: 10,
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{Touchpoint{"Display"}: struct{}{}},
+			Value:       *new(big.Float).SetFloat64(200.),
+			Journeys:    100,
+			Conversions: 2,
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Search"}:  struct{}{},
+				Touchpoint{"Display"}: struct{}{},
+			},
+			Value:       *new(big.Float).SetFloat64(1000.),
+			Journeys:    50,
+			Conversions: 10,
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{},
+			Value:       *new(big.Float).SetFloat64(100.),
+			Journeys:    200,
+			Conversions: 2,
+		},
+	}
+}
+
+func ExampleGetCausalValues() {
+	contributions := causalFixture()
+	causalValues := GetCausalValues(contributions)
+
+	for _, touchpoint := range causalValues.GetTouchpoints() {
+		causalValue := causalValues[touchpoint]
+		fmt.Println(touchpoint.Name, causalValue.Text('f', 2))
+	}
+	// Output:
+	// Display 489.47
+	// Search 1710.53
+}
+
+func TestConversionProbabilityValue(t *testing.T) {
+	function := ConversionProbabilityValue(causalFixture())
+	newCoalition := func(names ...string) map[Touchpoint]struct{} {
+		coalition := make(map[Touchpoint]struct{})
+		for _, name := range names {
+			coalition[Touchpoint{name}] = struct{}{}
+		}
+		return coalition
+	}
+
+	cases := []struct {
+		coalition map[Touchpoint]struct{}
+		want      float64
+	}{
+		{newCoalition(), 0.01},
+		{newCoalition("Search"), 0.1},
+		{newCoalition("Search", "Display"), 0.2},
+		// never observed, pooled from {}, {Search} and {Display}
+		{newCoalition("Display", "Email"), 4. / 300.},
+		{newCoalition("Search", "Display", "Email"), 24. / 450.},
+	}
+	for _, c := range cases {
+		value := function.GetValue(c.coalition)
+		if got, _ := value.Float64(); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("%v: got %f want %f", c.coalition, got, c.want)
+		}
+	}
+
+	// the table used by GetCausalValues agrees with evaluating every coalition on its own
+	table := newEmptyCoalitionTable(function.GetTouchpoints())
+	fillConversionProbabilities(table, function.getConversionCounts())
+	for mask := range table.values {
+		coalition := table.getCoalition(uint(mask))
+		value := function.GetValue(coalition)
+		if value.Cmp(&table.values[mask]) != 0 {
+			t.Errorf("%v: got %s want %s", coalition, table.values[mask].String(), value.String())
+		}
+	}
+}
+
+func TestGetCausalValues(t *testing.T) {
+	contributions := causalFixture()
+	causalValues := GetCausalValues(contributions)
+
+	if err := causalValues.CheckEfficiency(contributions[:3], 1e-9); err != nil {
+		t.Error(err)
+	}
+	for _, touchpoint := range GetAllTouchpoints(contributions) {
+		causalValue := causalValues[touchpoint]
+		expectedValue := GetCausalValue(touchpoint, contributions)
+		if causalValue.Cmp(&expectedValue) != 0 {
+			t.Errorf("%s: got %s want %s", touchpoint.Name, causalValue.String(), expectedValue.String())
+		}
+	}
+
+	// without non-converting journeys, no touchpoint changes the conversion probability and the value is split evenly
+	linearValues := GetCausalValues(contributionSetFixture())
+	for _, touchpoint := range GetAllTouchpoints(contributionSetFixture()) {
+		causalValue := linearValues[touchpoint]
+		linearValue := GetLinearValue(touchpoint, contributionSetFixture())
+
+		got, _ := causalValue.Float64()
+		want, _ := linearValue.Float64()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: got %f want %f", touchpoint, got, want)
+		}
+	}
+}
+
+func TestGetCausalValuesNegativeCredit(t *testing.T) {
+	// exposure to both touchpoints converts worse than to either one alone, so b lowers the conversion probability
+	contributions := []ContributionSet{
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{Touchpoint{"a"}: struct{}{}},
+			Journeys:    1000,
+			Conversions: 500,
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{Touchpoint{"b"}: struct{}{}},
+			Journeys:    1000,
+			Conversions: 100,
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{Touchpoint{"a"}: struct{}{}, Touchpoint{"b"}: struct{}{}},
+			Value:       *new(big.Float).SetFloat64(100.),
+			Journeys:    1000,
+			Conversions: 1,
+		},
+	}
+	causalValues, err := GetCausalValuesChecked(contributions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]float64{"a": 100., "b": 0.} {
+		causalValue := causalValues[Touchpoint{name}]
+		if got, _ := causalValue.Float64(); math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: got %f want %f", name, got, want)
+		}
+	}
+}
+
+func TestGetCausalValuesChecked(t *testing.T) {
+	if _, err := GetCausalValuesChecked(nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("got %v want ErrEmptyInput", err)
+	}
+	if _, err := GetCausalValueChecked(Touchpoint{"unknown"}, causalFixture()); !errors.Is(err, ErrUnknownTouchpoint) {
+		t.Errorf("got %v want ErrUnknownTouchpoint", err)
+	}
+
+	contributions := causalFixture()
+	contributions[0].Conversions = 200
+	if _, err := GetCausalValuesChecked(contributions); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("got %v want ErrInvalidCount", err)
+	}
+}
@@ -0,0 +1,177 @@
+package attribution
+
+import (
+	"io"
+	"math/big"
+	"sort"
+)
+
+// A CharacteristicFunction assigns a value to every coalition of touchpoints, which defines a cooperative game among
+// them. The value of the empty coalition doesn't affect Shapley values.
+type CharacteristicFunction interface {
+	// GetTouchpoints returns all touchpoints taking part in the game in sorted order.
+	GetTouchpoints() Touchpoints
+	// GetValue returns the value of the given coalition.
+	GetValue(coalition map[Touchpoint]struct{}) big.Float
+}
+
+// coalitionTabler is implemented by characteristic functions which compute the values of all coalitions at once faster
+// than by evaluating every coalition on its own.
+type coalitionTabler interface {
+	getCoalitionTable() coalitionTable
+}
+
+// newCharacteristicTable returns the coalition table of the given characteristic function.
+func newCharacteristicTable(function CharacteristicFunction) coalitionTable {
+	if tabler, ok := function.(coalitionTabler); ok {
+		return tabler.getCoalitionTable()
+	}
+
+	table := newEmptyCoalitionTable(function.GetTouchpoints())
+	for mask := range table.values {
+		// the table is transformed in place, so it must not share mantissas with the function's values
+		value := function.GetValue(table.getCoalition(uint(mask)))
+		table.values[mask].Set(&value)
+	}
+	return table
+}
+
+// ContainedValue is the characteristic function of GetCoalitionValue: a coalition achieves the value of all
+// contributions whose touchpoints it contains entirely.
+// Shapley values of this game coincide with GetLinearValue.
+type ContainedValue []ContributionSet
+
+// GetTouchpoints returns all touchpoints encountered in the contributions.
+func (function ContainedValue) GetTouchpoints() Touchpoints {
+	return GetAllTouchpoints(function)
+}
+
+// GetValue returns the total value of all contributions whose touchpoints are contained in the coalition.
+func (function ContainedValue) GetValue(coalition map[Touchpoint]struct{}) big.Float {
+	return GetCoalitionValue(coalition, function)
+}
+
+func (function ContainedValue) getCoalitionTable() coalitionTable {
+	return newCoalitionTable(function)
+}
+
+// IntersectingValue is the characteristic function under which a coalition achieves the value of all contributions
+// sharing at least one touchpoint with it.
+// Being the dual of ContainedValue, its Shapley values coincide with GetLinearValue as well.
+type IntersectingValue []ContributionSet
+
+// GetTouchpoints returns all touchpoints encountered in the contributions.
+func (function IntersectingValue) GetTouchpoints() Touchpoints {
+	return GetAllTouchpoints(function)
+}
+
+// GetValue returns the total value of all contributions with at least one touchpoint in the coalition.
+func (function IntersectingValue) GetValue(coalition map[Touchpoint]struct{}) big.Float {
+	value := new(big.Float)
+	for _, contribution := range function {
+		for touchpoint := range contribution.Touchpoints {
+			if _, found := coalition[touchpoint]; found {
+				value.Add(value, &contribution.Value)
+				break
+			}
+		}
+	}
+	return *value
+}
+
+func (function IntersectingValue) getCoalitionTable() coalitionTable {
+	// a coalition intersects exactly those contributions that aren't contained in its complement
+	contained := newCoalitionTable(function)
+	table := newEmptyCoalitionTable(contained.touchpoints)
+	grandCoalition := len(table.values) - 1
+	for mask := range table.values {
+		table.values[mask].Sub(&contained.values[grandCoalition], &contained.values[grandCoalition^mask])
+	}
+	return table
+}
+
+// ConversionRateValue is the characteristic function under which a coalition achieves the summed conversion rates of
+// all distinct touchpoint sets it contains, where the conversion rate of a set is the share of converting journeys among
+// all journeys exposed to exactly that set.
+// Values are conversion rates rather than values of contributions.
+type ConversionRateValue []ContributionSet
+
+// GetTouchpoints returns all touchpoints encountered in the contributions.
+func (function ConversionRateValue) GetTouchpoints() Touchpoints {
+	return GetAllTouchpoints(function)
+}
+
+// GetValue returns the summed conversion rates of all touchpoint sets contained in the coalition.
+func (function ConversionRateValue) GetValue(coalition map[Touchpoint]struct{}) big.Float {
+	return GetCoalitionValue(coalition, function.getConversionRates())
+}
+
+func (function ConversionRateValue) getCoalitionTable() coalitionTable {
+	return newCoalitionTable(function.getConversionRates())
+}
+
+// getConversionRates returns one contribution per distinct touchpoint set, valued at its conversion rate.
+func (function ConversionRateValue) getConversionRates() []ContributionSet {
+	conversionRates := AggregateContributionSets(function)
+	for index, contribution := range conversionRates {
+		conversionRates[index].Value.Quo(
+			new(big.Float).SetInt64(contribution.GetConversions()),
+			new(big.Float).SetInt64(contribution.GetJourneys()),
+		)
+	}
+	return conversionRates
+}
+
+// CoalitionValues is a characteristic function given by an explicit table of coalition values, such as the output of
+// an experiment or of an external model. Coalitions missing from the table have a value of zero.
+// The zero value is an empty table ready to use.
+type CoalitionValues struct {
+	touchpoints map[Touchpoint]struct{}
+	values      map[string]*big.Float
+}
+
+// Set sets the value of the given coalition to a copy of the given value.
+func (function *CoalitionValues) Set(coalition map[Touchpoint]struct{}, value big.Float) {
+	if function.values == nil {
+		function.touchpoints = make(map[Touchpoint]struct{})
+		function.values = make(map[string]*big.Float)
+	}
+	for touchpoint := range coalition {
+		function.touchpoints[touchpoint] = struct{}{}
+	}
+	function.values[getCoalitionKey(coalition)] = new(big.Float).Set(&value)
+}
+
+// GetTouchpoints returns all touchpoints occurring in any coalition of the table.
+func (function *CoalitionValues) GetTouchpoints() Touchpoints {
+	touchpoints := make(Touchpoints, 0, len(function.touchpoints))
+	for touchpoint := range function.touchpoints {
+		touchpoints = append(touchpoints, touchpoint)
+	}
+	sort.Sort(touchpoints)
+	return touchpoints
+}
+
+// GetValue returns a copy of the value of the given coalition, or zero if it is missing from the table.
+func (function *CoalitionValues) GetValue(coalition map[Touchpoint]struct{}) big.Float {
+	var value big.Float
+	if stored, found := function.values[getCoalitionKey(coalition)]; found {
+		value.Set(stored)
+	}
+	return value
+}
+
+// ReadCoalitionValuesCSV reads a table of coalition values from a CSV file in the format of ReadContributionsCSV, where
+// the path column lists the touchpoints of a coalition in any order. Values of repeated coalitions are summed.
+func ReadCoalitionValuesCSV(reader io.Reader, options CSVOptions) (*CoalitionValues, error) {
+	allContributions, err := ReadContributionsCSV(reader, options)
+	if err != nil {
+		return nil, err
+	}
+
+	function := new(CoalitionValues)
+	for _, contribution := range AggregateContributionSets(getContributionSets(allContributions)) {
+		function.Set(contribution.Touchpoints, contribution.Value)
+	}
+	return function, nil
+}
@@ -0,0 +1,152 @@
+package attribution
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func ExampleReadCoalitionValuesCSV() {
+	experiment := `coalition,lift
+search,100
+display,50
+display > search,200
+`
+	function, err := ReadCoalitionValuesCSV(strings.NewReader(experiment), CSVOptions{
+		PathColumn:  "coalition",
+		ValueColumn: "lift",
+	})
+	if err != nil {
+		panic(err)
+	}
+	shapleyValues := GetShapleyValuesWith(function)
+
+	for _, touchpoint := range function.GetTouchpoints() {
+		shapleyValue := shapleyValues[touchpoint]
+		fmt.Println(touchpoint.Name, shapleyValue.String())
+	}
+	// Output:
+	// display 75
+	// search 125
+}
+
+// evaluatedFunction hides the coalition table of a characteristic function, so that every coalition is evaluated on
+// its own.
+type evaluatedFunction struct {
+	CharacteristicFunction
+}
+
+func TestCharacteristicTables(t *testing.T) {
+	contributions := contributionSetFixture()
+	contributions[3].Journeys = 10
+	contributions[3].Conversions = 4
+
+	for _, function := range []CharacteristicFunction{
+		ContainedValue(contributions),
+		IntersectingValue(contributions),
+		ConversionRateValue(contributions),
+	} {
+		table := newCharacteristicTable(function)
+		evaluatedTable := newCharacteristicTable(evaluatedFunction{function})
+		for mask := range table.values {
+			got, _ := table.values[mask].Float64()
+			want, _ := evaluatedTable.values[mask].Float64()
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("%T: coalition %s: got %f want %f", function, table.getCoalition(uint(mask)), got, want)
+			}
+		}
+	}
+}
+
+func TestGetShapleyValuesWithContainedValue(t *testing.T) {
+	contributions := contributionSetFixture()
+	shapleyValues := GetShapleyValuesWith(ContainedValue(contributions))
+
+	for _, touchpoint := range GetAllTouchpoints(contributions) {
+		shapleyValue := shapleyValues[touchpoint]
+		linearValue := GetLinearValue(touchpoint, contributions)
+
+		got, _ := shapleyValue.Float64()
+		want, _ := linearValue.Float64()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: got %f want %f", touchpoint, got, want)
+		}
+	}
+}
+
+func TestGetShapleyValuesWithConversionRateValue(t *testing.T) {
+	contributions := []ContributionSet{
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{Touchpoint{"a"}: struct{}{}},
+			Journeys:    10,
+			Conversions: 2,
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{Touchpoint{"a"}: struct{}{}, Touchpoint{"b"}: struct{}{}},
+			Journeys:    5,
+			Conversions: 3,
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{Touchpoint{"b"}: struct{}{}},
+			Journeys:    4,
+			Conversions: 0,
+		},
+	}
+	shapleyValues := GetShapleyValuesWith(ConversionRateValue(contributions))
+
+	for name, want := range map[string]float64{"a": 0.5, "b": 0.3} {
+		shapleyValue := shapleyValues[Touchpoint{name}]
+		if got, _ := shapleyValue.Float64(); math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: got %f want %f", name, got, want)
+		}
+	}
+}
+
+func TestCoalitionValues(t *testing.T) {
+	var function CoalitionValues
+	coalition := map[Touchpoint]struct{}{Touchpoint{"b"}: struct{}{}, Touchpoint{"a"}: struct{}{}}
+
+	if value := function.GetValue(coalition); value.Sign() != 0 {
+		t.Errorf("got %s want 0", value.String())
+	}
+	function.Set(coalition, *big.NewFloat(3))
+	if value := function.GetValue(coalition); value.String() != "3" {
+		t.Errorf("got %s want 3", value.String())
+	}
+	if got := function.GetTouchpoints().String(); got != "[{a} {b}]" {
+		t.Errorf("got %s want [{a} {b}]", got)
+	}
+}
+
+func TestCoalitionValuesCopies(t *testing.T) {
+	var function CoalitionValues
+	coalition := map[Touchpoint]struct{}{Touchpoint{"a"}: struct{}{}}
+	value := *big.NewFloat(3.0000000001)
+	function.Set(coalition, value)
+
+	// neither the value passed to Set nor the one returned by GetValue may share its mantissa with the table
+	value.Sub(&value, big.NewFloat(2))
+	returned := function.GetValue(coalition)
+	returned.Sub(&returned, big.NewFloat(2.5))
+	if value := function.GetValue(coalition); value.Cmp(big.NewFloat(3.0000000001)) != 0 {
+		t.Errorf("got %s want 3.0000000001", value.String())
+	}
+}
+
+func TestGetShapleyValuesWithIntersectingValue(t *testing.T) {
+	contributions := contributionSetFixture()
+	shapleyValues := GetShapleyValuesWith(IntersectingValue(contributions))
+
+	for _, touchpoint := range GetAllTouchpoints(contributions) {
+		shapleyValue := shapleyValues[touchpoint]
+		linearValue := GetLinearValue(touchpoint, contributions)
+
+		got, _ := shapleyValue.Float64()
+		want, _ := linearValue.Float64()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: got %f want %f", touchpoint, got, want)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package attribution
 
 import (
+	"fmt"
+	"math"
 	"math/big"
+	"time"
 )
 
 // GetFirstTouchpointValue returns summed value of all contributions where the given touchpoints happened to be
@@ -19,6 +22,18 @@ func GetFirstTouchpointValue(touchpoint Touchpoint, allContributions []Contribut
 	return *firstTouchpointValue
 }
 
+// GetFirstTouchpointValueChecked is like GetFirstTouchpointValue, but returns an error for empty or non-finite input and for
+// touchpoints that don't occur in any contribution.
+func GetFirstTouchpointValueChecked(touchpoint Touchpoint, allContributions []Contribution) (big.Float, error) {
+	if err := validateContributions(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	return GetFirstTouchpointValue(touchpoint, allContributions), nil
+}
+
 // GetLastTouchpointValue returns summed value of all contributions where the given touchpoints happened to be
 // last in its list of contributors.
 func GetLastTouchpointValue(touchpoint Touchpoint, allContributions []Contribution) big.Float {
@@ -35,6 +50,18 @@ func GetLastTouchpointValue(touchpoint Touchpoint, allContributions []Contributi
 	return *lastTouchpointValue
 }
 
+// GetLastTouchpointValueChecked is like GetLastTouchpointValue, but returns an error for empty or non-finite input and for
+// touchpoints that don't occur in any contribution.
+func GetLastTouchpointValueChecked(touchpoint Touchpoint, allContributions []Contribution) (big.Float, error) {
+	if err := validateContributions(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	return GetLastTouchpointValue(touchpoint, allContributions), nil
+}
+
 // GetLinearValue returns the linear value (ignoring repetition) of a given touchpoint summed over all contributions.
 // The linear value without repititions for Contribution objecs can best be calculated by first transformating them
 // to ContributionSet objects with the Set() method and then applying this function.
@@ -46,10 +73,10 @@ func GetLinearValue(touchpoint Touchpoint, allContributions []ContributionSet) b
 		for candidate, _ := range contribution.Touchpoints {
 			if touchpoint == candidate {
 				numberTouchpoints := float64(len(contribution.Touchpoints))
-				addedValue := contribution.Value
+				addedValue := new(big.Float).Set(&contribution.Value)
 				// distribute value equally among all contributors
-				addedValue.Quo(&addedValue, new(big.Float).SetFloat64(numberTouchpoints))
-				linearValue.Add(linearValue, &addedValue)
+				addedValue.Quo(addedValue, new(big.Float).SetFloat64(numberTouchpoints))
+				linearValue.Add(linearValue, addedValue)
 				break
 			}
 		}
@@ -58,6 +85,18 @@ func GetLinearValue(touchpoint Touchpoint, allContributions []ContributionSet) b
 	return *linearValue
 }
 
+// GetLinearValueChecked is like GetLinearValue, but returns an error for empty or non-finite input and for
+// touchpoints that don't occur in any contribution.
+func GetLinearValueChecked(touchpoint Touchpoint, allContributions []ContributionSet) (big.Float, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateSetTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	return GetLinearValue(touchpoint, allContributions), nil
+}
+
 // GetRepeatedLinearValue returns the linear value (with repition) of a given touchpoint summed over all contributions.
 func GetRepeatedLinearValue(touchpoint Touchpoint, allContributions []Contribution) big.Float {
 	linearValue := new(big.Float)
@@ -72,14 +111,231 @@ func GetRepeatedLinearValue(touchpoint Touchpoint, allContributions []Contributi
 		}
 		if touchpointContributions > 0 {
 			numberTouchpoints := float64(len(contribution.Touchpoints))
-			addedValue := contribution.Value
+			addedValue := new(big.Float).Set(&contribution.Value)
 			// distribute value equally among all contributors according to their number of contributions
-			addedValue.Mul(&addedValue, new(big.Float).SetFloat64(float64(touchpointContributions)))
-			addedValue.Quo(&addedValue, new(big.Float).SetFloat64(numberTouchpoints))
-			linearValue.Add(linearValue, &addedValue)
+			addedValue.Mul(addedValue, new(big.Float).SetFloat64(float64(touchpointContributions)))
+			addedValue.Quo(addedValue, new(big.Float).SetFloat64(numberTouchpoints))
+			linearValue.Add(linearValue, addedValue)
 		}
 
 	}
 
 	return *linearValue
 }
+
+// GetRepeatedLinearValueChecked is like GetRepeatedLinearValue, but returns an error for empty or non-finite input and for
+// touchpoints that don't occur in any contribution.
+func GetRepeatedLinearValueChecked(touchpoint Touchpoint, allContributions []Contribution) (big.Float, error) {
+	if err := validateContributions(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	return GetRepeatedLinearValue(touchpoint, allContributions), nil
+}
+
+// GetTimeDecayValue returns the time-decay value of a given touchpoint summed over all contributions.
+// The value of each contribution is distributed among its touchpoint occurrences with weights halving every halfLife
+// before the conversion. Since only the relative weights matter, contributions whose touchpoints all lie long before the
+// conversion keep their full value. Contributions without timestamps for all touchpoints are distributed as in
+// GetRepeatedLinearValue.
+func GetTimeDecayValue(touchpoint Touchpoint, allContributions []Contribution, halfLife time.Duration) big.Float {
+	timeDecayValue := new(big.Float)
+
+	for _, contribution := range allContributions {
+		if _, found := findTouchpoint(touchpoint, contribution.Touchpoints); !found {
+			continue
+		}
+		hasTimestamps := contribution.HasTimestamps() && halfLife > 0
+		ages := make([]time.Duration, len(contribution.Touchpoints))
+		var minAge time.Duration
+		if hasTimestamps {
+			conversionTime := contribution.GetConversionTime()
+			for index, timestamp := range contribution.Timestamps {
+				ages[index] = conversionTime.Sub(timestamp)
+				if ages[index] < 0 {
+					ages[index] = 0
+				}
+				if index == 0 || ages[index] < minAge {
+					minAge = ages[index]
+				}
+			}
+		}
+		touchpointWeight := 0.
+		totalWeight := 0.
+		for index, candidate := range contribution.Touchpoints {
+			weight := 1.
+			if hasTimestamps {
+				// only differences in age matter, so measuring them from the most recent touchpoint keeps its weight
+				// at one and the total weight from underflowing
+				weight = math.Exp2(-float64(ages[index]-minAge) / float64(halfLife))
+			}
+			if touchpoint == candidate {
+				touchpointWeight += weight
+			}
+			totalWeight += weight
+		}
+		// distribute value among all contributors according to their decayed weights
+		addedValue := new(big.Float).SetFloat64(touchpointWeight / totalWeight)
+		addedValue.Mul(addedValue, &contribution.Value)
+		timeDecayValue.Add(timeDecayValue, addedValue)
+	}
+
+	return *timeDecayValue
+}
+
+// GetTimeDecayValueChecked is like GetTimeDecayValue, but returns an error for empty or non-finite input, for
+// touchpoints that don't occur in any contribution, for contributions with missing or inconsistent timestamps and for
+// non-positive half-lives.
+func GetTimeDecayValueChecked(touchpoint Touchpoint, allContributions []Contribution, halfLife time.Duration) (big.Float, error) {
+	if err := validateContributions(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateTimestamps(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if halfLife <= 0 {
+		return big.Float{}, fmt.Errorf("%w: half-life %s is not positive", ErrInvalidParameter, halfLife)
+	}
+	return GetTimeDecayValue(touchpoint, allContributions, halfLife), nil
+}
+
+// PositionWeights returns the weight of every position in a path of the given length.
+// The weights are normalized to add up to one.
+type PositionWeights func(length int) []float64
+
+// UShapedWeights provides the classic U-shaped position weights: 40% each for the first and the last touchpoint, and
+// 20% spread evenly among all touchpoints in between.
+// Paths with a single touchpoint give it full weight and paths with two touchpoints split the weight evenly.
+func UShapedWeights(length int) []float64 {
+	switch length {
+	case 0:
+		return []float64{}
+	case 1:
+		return []float64{1.}
+	case 2:
+		return []float64{.5, .5}
+	}
+	weights := make([]float64, length)
+	for position := 1; position < length-1; position++ {
+		weights[position] = .2 / float64(length-2)
+	}
+	weights[0] = .4
+	weights[length-1] = .4
+	return weights
+}
+
+// WShapedWeights provides W-shaped position weights: 30% each for the first touchpoint, the anchor touchpoint returned
+// by anchor, and the last touchpoint, and 10% spread evenly among all other touchpoints.
+// The anchor is clamped to lie strictly between the first and the last touchpoint. Paths with up to three touchpoints
+// split the weight evenly.
+func WShapedWeights(anchor func(length int) int) PositionWeights {
+	return func(length int) []float64 {
+		if length <= 3 {
+			weights := make([]float64, length)
+			for position := range weights {
+				weights[position] = 1. / float64(length)
+			}
+			return weights
+		}
+		anchorPosition := anchor(length)
+		if anchorPosition < 1 {
+			anchorPosition = 1
+		}
+		if anchorPosition > length-2 {
+			anchorPosition = length - 2
+		}
+		weights := make([]float64, length)
+		for position := range weights {
+			weights[position] = .1 / float64(length-3)
+		}
+		weights[0] = .3
+		weights[anchorPosition] = .3
+		weights[length-1] = .3
+		return weights
+	}
+}
+
+// MiddleAnchor returns the middle position of a path of the given length. It can be used as anchor of
+// WShapedWeights.
+func MiddleAnchor(length int) int {
+	return length / 2
+}
+
+// GetPositionBasedValue returns the position-based value of a given touchpoint summed over all contributions.
+// The value of each contribution is distributed among its touchpoint occurrences according to the given position
+// weights. Repeated touchpoints receive the weights of all their occurrences, as in GetRepeatedLinearValue.
+// If the weights for a path don't match its length or don't add up to a positive value, the path's value is
+// distributed evenly.
+func GetPositionBasedValue(touchpoint Touchpoint, allContributions []Contribution, weights PositionWeights) big.Float {
+	positionBasedValue := new(big.Float)
+
+	for _, contribution := range allContributions {
+		length := len(contribution.Touchpoints)
+		if _, found := findTouchpoint(touchpoint, contribution.Touchpoints); !found {
+			continue
+		}
+		positionWeights := weights(length)
+		totalWeight := 0.
+		for _, weight := range positionWeights {
+			totalWeight += weight
+		}
+		if len(positionWeights) != length || !(totalWeight > 0) {
+			positionWeights = make([]float64, length)
+			for position := range positionWeights {
+				positionWeights[position] = 1.
+			}
+			totalWeight = float64(length)
+		}
+
+		touchpointWeight := 0.
+		for position, candidate := range contribution.Touchpoints {
+			if touchpoint == candidate {
+				touchpointWeight += positionWeights[position]
+			}
+		}
+		// distribute value among all contributors according to their positions
+		addedValue := new(big.Float).SetFloat64(touchpointWeight / totalWeight)
+		addedValue.Mul(addedValue, &contribution.Value)
+		positionBasedValue.Add(positionBasedValue, addedValue)
+	}
+
+	return *positionBasedValue
+}
+
+// GetPositionBasedValueChecked is like GetPositionBasedValue, but returns an error for empty or non-finite input, for
+// touchpoints that don't occur in any contribution and for position weights that don't match the length of a path,
+// are negative or don't add up to a positive value.
+func GetPositionBasedValueChecked(touchpoint Touchpoint, allContributions []Contribution, weights PositionWeights) (big.Float, error) {
+	if err := validateContributions(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	for _, contribution := range allContributions {
+		length := len(contribution.Touchpoints)
+		if length == 0 {
+			continue
+		}
+		positionWeights := weights(length)
+		if len(positionWeights) != length {
+			return big.Float{}, fmt.Errorf("%w: got %d position weights for a path of length %d", ErrInvalidParameter, len(positionWeights), length)
+		}
+		totalWeight := 0.
+		for _, weight := range positionWeights {
+			if weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+				return big.Float{}, fmt.Errorf("%w: invalid position weight %f for a path of length %d", ErrInvalidParameter, weight, length)
+			}
+			totalWeight += weight
+		}
+		if totalWeight == 0 {
+			return big.Float{}, fmt.Errorf("%w: position weights for a path of length %d add up to zero", ErrInvalidParameter, length)
+		}
+	}
+	return GetPositionBasedValue(touchpoint, allContributions, weights), nil
+}
@@ -1,9 +1,12 @@
 package attribution
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"testing"
+	"time"
 )
 
 func ExampleGetFirstTouchpointValue() {
@@ -184,3 +187,296 @@ func TestRepeatedGetLinearValue(t *testing.T) {
 		t.Errorf("got %f want %f", got, want)
 	}
 }
+
+func TestClassicalValuesChecked(t *testing.T) {
+	contributions := contributionFixture()
+	var contributionSets []ContributionSet
+	for _, contribution := range contributions {
+		contributionSets = append(contributionSets, contribution.Set())
+	}
+	unknownTouchpoint := touchpointFixture()[9]
+
+	checkedFunctions := map[string]func(Touchpoint) (big.Float, error){
+		"first": func(touchpoint Touchpoint) (big.Float, error) {
+			return GetFirstTouchpointValueChecked(touchpoint, contributions)
+		},
+		"last": func(touchpoint Touchpoint) (big.Float, error) {
+			return GetLastTouchpointValueChecked(touchpoint, contributions)
+		},
+		"linear": func(touchpoint Touchpoint) (big.Float, error) {
+			return GetLinearValueChecked(touchpoint, contributionSets)
+		},
+		"repeated linear": func(touchpoint Touchpoint) (big.Float, error) {
+			return GetRepeatedLinearValueChecked(touchpoint, contributions)
+		},
+	}
+
+	for name, checkedFunction := range checkedFunctions {
+		if _, err := checkedFunction(touchpointFixture()[2]); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		if _, err := checkedFunction(unknownTouchpoint); !errors.Is(err, ErrUnknownTouchpoint) {
+			t.Errorf("%s: got %v want %v", name, err, ErrUnknownTouchpoint)
+		}
+	}
+
+	if _, err := GetFirstTouchpointValueChecked(unknownTouchpoint, nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("got %v want %v", err, ErrEmptyInput)
+	}
+	if _, err := GetLinearValueChecked(unknownTouchpoint, []ContributionSet{}); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("got %v want %v", err, ErrEmptyInput)
+	}
+}
+
+func ExampleGetTimeDecayValue() {
+	conversionTime := time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC)
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Touchpoint 1"},
+				Touchpoint{"Touchpoint 2"},
+			},
+			Value: *new(big.Float).SetFloat64(300.),
+			Timestamps: []time.Time{
+				conversionTime.AddDate(0, 0, -14),
+				conversionTime.AddDate(0, 0, -7),
+			},
+			ConversionTime: conversionTime,
+		},
+	}
+	halfLife := 7 * 24 * time.Hour
+
+	for _, touchpoint := range []Touchpoint{Touchpoint{"Touchpoint 1"}, Touchpoint{"Touchpoint 2"}} {
+		timeDecayValue := GetTimeDecayValue(touchpoint, contributions, halfLife)
+		fmt.Println(touchpoint.Name, timeDecayValue.String())
+	}
+	// Output:
+	// Touchpoint 1 100
+	// Touchpoint 2 200
+}
+
+func TestGetTimeDecayValue(t *testing.T) {
+	contributions := contributionFixture()
+	touchpoint := touchpointFixture()[2]
+
+	// without timestamps, time decay reduces to linear attribution with repetition
+	timeDecayValue := GetTimeDecayValue(touchpoint, contributions, time.Hour)
+	expectedValue := GetRepeatedLinearValue(touchpoint, contributions)
+	got, _ := timeDecayValue.Float64()
+	want, _ := expectedValue.Float64()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("got %f want %f", got, want)
+	}
+
+	// the conversion time defaults to the last touchpoint
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for index := range contributions {
+		for position := range contributions[index].Touchpoints {
+			contributions[index].Timestamps = append(contributions[index].Timestamps, start.Add(time.Duration(position)*time.Hour))
+		}
+	}
+	timeDecayValue = GetTimeDecayValue(touchpointFixture()[4], contributions, time.Hour)
+	got, _ = timeDecayValue.Float64()
+	// a touchpoint k hours before the end of a path of length l receives a share of 2^-k / (2 - 2^-(l-1))
+	want = 100.*(8./15.+8./31.) + 200.*(4./7.+4./15.+4./31.) + 300.*(2./3.+2./7.+2./15.+2./31.) +
+		400.*(1.+1./3.+1./7.+1./15.+1./31.)
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("got %f want %f", got, want)
+	}
+}
+
+func TestGetTimeDecayValueOldTouchpoints(t *testing.T) {
+	// weights relative to the conversion would underflow for touchpoints more than about 1075 half-lives old
+	conversionTime := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	first, second := Touchpoint{"Touchpoint 1"}, Touchpoint{"Touchpoint 2"}
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints:    []Touchpoint{first, second},
+			Value:          *new(big.Float).SetFloat64(100.),
+			Timestamps:     []time.Time{conversionTime.Add(-60 * 24 * time.Hour), conversionTime.Add(-59 * 24 * time.Hour)},
+			ConversionTime: conversionTime,
+		},
+	}
+
+	firstValue, err := GetTimeDecayValueChecked(first, contributions, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secondValue, err := GetTimeDecayValueChecked(second, contributions, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ := secondValue.Float64()
+	want := 100. / (1. + math.Exp2(-24))
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("got %f want %f", got, want)
+	}
+	got, _ = firstValue.Float64()
+	want = 100. * math.Exp2(-24) / (1. + math.Exp2(-24))
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("got %g want %g", got, want)
+	}
+}
+
+func TestGetTimeDecayValueChecked(t *testing.T) {
+	conversionTime := time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC)
+	touchpoint := Touchpoint{"Touchpoint 1"}
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints:    []Touchpoint{touchpoint},
+			Value:          *new(big.Float).SetFloat64(100.),
+			Timestamps:     []time.Time{conversionTime.Add(-time.Hour)},
+			ConversionTime: conversionTime,
+		},
+	}
+
+	if _, err := GetTimeDecayValueChecked(touchpoint, contributions, time.Hour); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := GetTimeDecayValueChecked(touchpoint, contributions, 0); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("got %v want %v", err, ErrInvalidParameter)
+	}
+
+	contributions[0].Timestamps[0] = conversionTime.Add(time.Hour)
+	if _, err := GetTimeDecayValueChecked(touchpoint, contributions, time.Hour); !errors.Is(err, ErrInvalidTimestamps) {
+		t.Errorf("got %v want %v", err, ErrInvalidTimestamps)
+	}
+
+	contributions[0].Timestamps = nil
+	if _, err := GetTimeDecayValueChecked(touchpoint, contributions, time.Hour); !errors.Is(err, ErrInvalidTimestamps) {
+		t.Errorf("got %v want %v", err, ErrInvalidTimestamps)
+	}
+}
+
+func ExampleGetPositionBasedValue() {
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Touchpoint 1"},
+				Touchpoint{"Touchpoint 2"},
+				Touchpoint{"Touchpoint 3"},
+				Touchpoint{"Touchpoint 4"},
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Touchpoint 1"},
+				Touchpoint{"Touchpoint 2"},
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+	}
+
+	for _, touchpoint := range []Touchpoint{Touchpoint{"Touchpoint 1"}, Touchpoint{"Touchpoint 3"}} {
+		positionBasedValue := GetPositionBasedValue(touchpoint, contributions, UShapedWeights)
+		fmt.Println(touchpoint.Name, positionBasedValue.String())
+	}
+	// Output:
+	// Touchpoint 1 90
+	// Touchpoint 3 10
+}
+
+func TestPositionWeights(t *testing.T) {
+	cases := []struct {
+		name    string
+		weights PositionWeights
+		length  int
+		want    []float64
+	}{
+		{"U-shaped single", UShapedWeights, 1, []float64{1.}},
+		{"U-shaped pair", UShapedWeights, 2, []float64{.5, .5}},
+		{"U-shaped", UShapedWeights, 4, []float64{.4, .1, .1, .4}},
+		{"W-shaped single", WShapedWeights(MiddleAnchor), 1, []float64{1.}},
+		{"W-shaped pair", WShapedWeights(MiddleAnchor), 2, []float64{.5, .5}},
+		{"W-shaped triple", WShapedWeights(MiddleAnchor), 3, []float64{1. / 3., 1. / 3., 1. / 3.}},
+		{"W-shaped", WShapedWeights(MiddleAnchor), 5, []float64{.3, .05, .3, .05, .3}},
+		{"W-shaped clamped", WShapedWeights(func(int) int { return 0 }), 4, []float64{.3, .3, .1, .3}},
+	}
+
+	for _, c := range cases {
+		got := c.weights(c.length)
+		if len(got) != len(c.want) {
+			t.Errorf("%s: got %v want %v", c.name, got, c.want)
+			continue
+		}
+		for position := range got {
+			if math.Abs(got[position]-c.want[position]) > 1e-12 {
+				t.Errorf("%s: got %v want %v", c.name, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestGetPositionBasedValue(t *testing.T) {
+	contributions := contributionFixture()
+	touchpoint := touchpointFixture()[2]
+
+	lastTouchWeights := func(length int) []float64 {
+		weights := make([]float64, length)
+		weights[length-1] = 1.
+		return weights
+	}
+	positionBasedValue := GetPositionBasedValue(touchpoint, contributions, lastTouchWeights)
+	expectedValue := GetLastTouchpointValue(touchpoint, contributions)
+	if positionBasedValue.Cmp(&expectedValue) != 0 {
+		t.Errorf("got %s want %s", positionBasedValue.String(), expectedValue.String())
+	}
+
+	// invalid weights fall back to linear attribution with repetition
+	invalidWeights := func(length int) []float64 {
+		return nil
+	}
+	positionBasedValue = GetPositionBasedValue(touchpoint, contributions, invalidWeights)
+	expectedValue = GetRepeatedLinearValue(touchpoint, contributions)
+	got, _ := positionBasedValue.Float64()
+	want, _ := expectedValue.Float64()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("got %f want %f", got, want)
+	}
+
+	if _, err := GetPositionBasedValueChecked(touchpoint, contributions, invalidWeights); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("got %v want %v", err, ErrInvalidParameter)
+	}
+	if _, err := GetPositionBasedValueChecked(touchpoint, contributions, UShapedWeights); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGetPositionBasedValueRepeated(t *testing.T) {
+	touchpoint := Touchpoint{"Touchpoint 1"}
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: []Touchpoint{touchpoint, Touchpoint{"Touchpoint 2"}, touchpoint},
+			Value:       *new(big.Float).SetFloat64(100.),
+		},
+	}
+
+	positionBasedValue := GetPositionBasedValue(touchpoint, contributions, UShapedWeights)
+	if got, _ := positionBasedValue.Float64(); math.Abs(got-80.) > 1e-9 {
+		t.Errorf("got %f want %f", got, 80.)
+	}
+}
+
+func TestLinearValuesKeepContributions(t *testing.T) {
+	// summed values leave spare capacity in their mantissas, which must not be written to
+	contributions := contributionFixture()
+	want := contributionFixture()
+	for index := range contributions {
+		contributions[index].Value.Add(&contributions[index].Value, &want[index].Value)
+		want[index].Value.Add(&want[index].Value, &want[index].Value)
+	}
+	contributionSets := getContributionSets(contributions)
+
+	for _, touchpoint := range touchpointFixture() {
+		GetLinearValue(touchpoint, contributionSets)
+		GetRepeatedLinearValue(touchpoint, contributions)
+	}
+
+	for index := range contributions {
+		if contributions[index].Value.Cmp(&want[index].Value) != 0 {
+			t.Errorf("contribution %d: got %s want %s", index, contributions[index].Value.String(), want[index].Value.String())
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Command attribution runs multi-channel attribution models on channel-path exports.
+//
+// Usage:
+//
+//	attribution [flags] [file ...]
+//
+// Paths are read from the given CSV or NDJSON files, or from standard input if no files are given. The credits of
+// every touchpoint are printed as a table with one column per model, or written as JSON or CSV.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/KappaDistributive/attribution"
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	models       []string
+	inputFormat  string
+	outputFormat string
+	csvOptions   attribution.CSVOptions
+	normalize    string
+	depth        int
+	files        []string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command with the given arguments and returns its exit code.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	opts, err := parseFlags(args, stdout, stderr)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if opts == nil {
+		return 0
+	}
+
+	allContributions, err := readContributions(opts, stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if opts.normalize != "" {
+		normalizer, err := loadNormalizer(opts.normalize)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		allContributions = normalizer.NormalizeContributions(allContributions)
+	}
+	if opts.depth > 0 {
+		allContributions = attribution.RollUpContributions(allContributions, opts.depth)
+	}
+
+	results := make([]attribution.AttributionResult, len(opts.models))
+	for index, name := range opts.models {
+		model, err := attribution.GetModel(name)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		if results[index], err = model.Attribute(allContributions); err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", name, err)
+			return 1
+		}
+	}
+
+	if err := writeResults(stdout, opts.outputFormat, opts.models, results); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// parseFlags parses the command-line flags. If only the list of models was requested, it is printed and nil options
+// are returned.
+func parseFlags(args []string, stdout io.Writer, stderr io.Writer) (*options, error) {
+	flags := flag.NewFlagSet("attribution", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: attribution [flags] [file ...]")
+		flags.PrintDefaults()
+	}
+
+	models := flags.String("models", "first_touchpoint,last_touchpoint,linear,shapley", "comma-separated list of models to run")
+	listModels := flags.Bool("list-models", false, "list all available models and exit")
+	inputFormat := flags.String("input", "", "input format: csv or ndjson (default: derived from the file extension, csv for stdin)")
+	outputFormat := flags.String("output", "table", "output format: table, json or csv")
+	pathColumn := flags.String("path-column", "path", "name of the CSV column holding the path")
+	separator := flags.String("separator", ">", "separator between touchpoints of a path")
+	valueColumn := flags.String("value-column", "value", "name of the CSV column holding the value")
+	journeysColumn := flags.String("journeys-column", "", "name of the CSV column holding the number of journeys")
+	conversionsColumn := flags.String("conversions-column", "", "name of the CSV column holding the number of conversions")
+	normalize := flags.String("normalize", "", "JSON file with rules rewriting touchpoint names before attribution")
+	depth := flags.Int("depth", 0, "roll hierarchical touchpoints up to this depth (0 keeps all levels)")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *listModels {
+		for _, name := range attribution.GetModelNames() {
+			fmt.Fprintln(stdout, name)
+		}
+		return nil, nil
+	}
+
+	opts := &options{
+		inputFormat:  *inputFormat,
+		outputFormat: *outputFormat,
+		csvOptions: attribution.CSVOptions{
+			PathColumn:        *pathColumn,
+			Separator:         *separator,
+			ValueColumn:       *valueColumn,
+			JourneysColumn:    *journeysColumn,
+			ConversionsColumn: *conversionsColumn,
+		},
+		normalize: *normalize,
+		depth:     *depth,
+		files:     flags.Args(),
+	}
+	for _, name := range strings.Split(*models, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.models = append(opts.models, name)
+		}
+	}
+	if len(opts.models) == 0 {
+		return nil, errors.New("no models given")
+	}
+	if opts.depth < 0 {
+		return nil, fmt.Errorf("negative depth %d", opts.depth)
+	}
+	switch opts.inputFormat {
+	case "", "csv", "ndjson":
+	default:
+		return nil, fmt.Errorf("unknown input format %q", opts.inputFormat)
+	}
+	switch opts.outputFormat {
+	case "table", "json", "csv":
+	default:
+		return nil, fmt.Errorf("unknown output format %q", opts.outputFormat)
+	}
+
+	return opts, nil
+}
+
+// readContributions reads the contributions of all input files, or of stdin if no files are given.
+func readContributions(opts *options, stdin io.Reader) ([]attribution.Contribution, error) {
+	if len(opts.files) == 0 {
+		return readContributionsFrom(stdin, opts.inputFormat, opts.csvOptions)
+	}
+
+	var allContributions []attribution.Contribution
+	for _, file := range opts.files {
+		format := opts.inputFormat
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+		}
+
+		contributions, err := readContributionsFile(file, stdin, format, opts.csvOptions)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		allContributions = append(allContributions, contributions...)
+	}
+	return allContributions, nil
+}
+
+// loadNormalizer reads the normalization rules of the given JSON file.
+func loadNormalizer(file string) (attribution.Normalizer, error) {
+	reader, err := os.Open(file)
+	if err != nil {
+		return attribution.Normalizer{}, err
+	}
+	defer reader.Close()
+
+	normalizer, err := attribution.LoadNormalizer(reader)
+	if err != nil {
+		return attribution.Normalizer{}, fmt.Errorf("%s: %w", file, err)
+	}
+	return normalizer, nil
+}
+
+// readContributionsFile reads contributions in the given format from a file, or from stdin if file is "-".
+func readContributionsFile(file string, stdin io.Reader, format string, csvOptions attribution.CSVOptions) ([]attribution.Contribution, error) {
+	if file == "-" {
+		return readContributionsFrom(stdin, format, csvOptions)
+	}
+	reader, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return readContributionsFrom(reader, format, csvOptions)
+}
+
+// readContributionsFrom reads contributions in the given format. NDJSON is used for "ndjson" and "jsonl", CSV
+// otherwise.
+func readContributionsFrom(reader io.Reader, format string, csvOptions attribution.CSVOptions) ([]attribution.Contribution, error) {
+	switch format {
+	case "ndjson", "jsonl":
+		return attribution.ReadContributionsNDJSON(reader)
+	default:
+		return attribution.ReadContributionsCSV(reader, csvOptions)
+	}
+}
+
+// writeResults writes the results of all models in the given format.
+func writeResults(writer io.Writer, format string, models []string, results []attribution.AttributionResult) error {
+	var touchpoints attribution.Touchpoints
+	seen := make(map[attribution.Touchpoint]struct{})
+	for _, result := range results {
+		for _, touchpoint := range result.GetTouchpoints() {
+			if _, found := seen[touchpoint]; !found {
+				seen[touchpoint] = struct{}{}
+				touchpoints = append(touchpoints, touchpoint)
+			}
+		}
+	}
+	sort.Sort(touchpoints)
+
+	switch format {
+	case "json":
+		encoded := make(map[string]attribution.AttributionResult, len(models))
+		for index, name := range models {
+			encoded[name] = results[index]
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(encoded)
+	case "csv":
+		csvWriter := csv.NewWriter(writer)
+		if err := csvWriter.Write(append([]string{"touchpoint"}, models...)); err != nil {
+			return err
+		}
+		for _, touchpoint := range touchpoints {
+			record := []string{touchpoint.Name}
+			for _, result := range results {
+				value := result[touchpoint]
+				record = append(record, value.Text('g', -1))
+			}
+			if err := csvWriter.Write(record); err != nil {
+				return err
+			}
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	default:
+		tableWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tableWriter, "touchpoint\t%s\n", strings.Join(models, "\t"))
+		for _, touchpoint := range touchpoints {
+			cells := []string{touchpoint.Name}
+			for _, result := range results {
+				value := result[touchpoint]
+				cells = append(cells, value.Text('f', 2))
+			}
+			fmt.Fprintf(tableWriter, "%s\n", strings.Join(cells, "\t"))
+		}
+		totals := []string{"total"}
+		for _, result := range results {
+			total := result.GetTotal()
+			totals = append(totals, total.Text('f', 2))
+		}
+		fmt.Fprintf(tableWriter, "%s\n", strings.Join(totals, "\t"))
+		return tableWriter.Flush()
+	}
+}
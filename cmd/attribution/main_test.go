@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPaths = `path,value
+Search > Display,100
+Display,50
+`
+
+func TestRunTable(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"-models", "first_touchpoint,linear"}, strings.NewReader(testPaths), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("got exit code %d: %s", code, stderr.String())
+	}
+
+	want := `touchpoint  first_touchpoint  linear
+display     50.00             100.00
+search      100.00            50.00
+total       150.00            150.00
+`
+	if stdout.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", stdout.String(), want)
+	}
+}
+
+func TestRunCSV(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"-models", "last_touchpoint", "-output", "csv"}, strings.NewReader(testPaths), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("got exit code %d: %s", code, stderr.String())
+	}
+
+	want := "touchpoint,last_touchpoint\ndisplay,150\nsearch,0\n"
+	if stdout.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", stdout.String(), want)
+	}
+}
+
+func TestRunNDJSONFile(t *testing.T) {
+	directory, err := ioutil.TempDir("", "attribution")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	file := filepath.Join(directory, "paths.ndjson")
+	data := `{"touchpoints":["search","display"],"value":"100"}` + "\n" + `{"touchpoints":["display"],"value":"50"}` + "\n"
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"-models", "shapley", "-output", "json", file}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("got exit code %d: %s", code, stderr.String())
+	}
+
+	want := `{
+  "shapley": {
+    "display": "100",
+    "search": "50"
+  }
+}
+`
+	if stdout.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", stdout.String(), want)
+	}
+}
+
+func TestRunNormalize(t *testing.T) {
+	directory, err := ioutil.TempDir("", "attribution")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	file := filepath.Join(directory, "rules.json")
+	config := `{"rules": [{"type": "exact", "mapping": {"search": "paid"}}], "fallback": "other"}`
+	if err := ioutil.WriteFile(file, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"-models", "linear", "-output", "csv", "-normalize", file}, strings.NewReader(testPaths), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("got exit code %d: %s", code, stderr.String())
+	}
+
+	want := "touchpoint,linear\nother,100\npaid,50\n"
+	if stdout.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", stdout.String(), want)
+	}
+}
+
+func TestRunDepth(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	paths := "path,value\nsearch|brand > display|video,100\nsearch|generic,50\n"
+
+	code := run([]string{"-models", "linear", "-output", "csv", "-depth", "1"}, strings.NewReader(paths), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("got exit code %d: %s", code, stderr.String())
+	}
+
+	want := "touchpoint,linear\ndisplay,50\nsearch,100\n"
+	if stdout.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", stdout.String(), want)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		args  []string
+		input string
+		code  int
+	}{
+		{"unknown model", []string{"-models", "unknown"}, testPaths, 1},
+		{"unknown output", []string{"-output", "xml"}, testPaths, 2},
+		{"malformed input", nil, "path,value\na > ,1\n", 1},
+		{"missing file", []string{"missing.csv"}, "", 1},
+		{"missing rules", []string{"-normalize", "missing.json"}, testPaths, 1},
+		{"negative depth", []string{"-depth", "-1"}, testPaths, 2},
+	}
+
+	for _, c := range cases {
+		var stdout, stderr bytes.Buffer
+		if code := run(c.args, strings.NewReader(c.input), &stdout, &stderr); code != c.code {
+			t.Errorf("%s: got exit code %d want %d", c.name, code, c.code)
+		}
+		if stderr.Len() == 0 {
+			t.Errorf("%s: missing error message", c.name)
+		}
+	}
+}
+
+func TestRunListModels(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"-list-models"}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("got exit code %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "shapley\n") {
+		t.Errorf("got %s, missing shapley", stdout.String())
+	}
+}
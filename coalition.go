@@ -0,0 +1,146 @@
+package attribution
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// A coalitionTable holds the value of every coalition of a fixed list of touchpoints.
+// Touchpoints are interned to bit positions, so that each coalition is represented by a bitmask which serves as index
+// into the table.
+type coalitionTable struct {
+	touchpoints Touchpoints         // touchpoints[i] is represented by the bit 1 << i
+	indices     map[Touchpoint]uint // inverse of touchpoints
+	values      []big.Float         // values[mask] is the value of the coalition represented by mask
+}
+
+// newCoalitionTable interns all touchpoints of the given contributions and computes the value of every coalition in
+// the sense of GetCoalitionValue.
+// Each contribution's value is added once at the bitmask of its touchpoints, followed by a subset-sum (zeta) transform
+// which yields the value of all 2^n coalitions in O(n * 2^n) operations.
+func newCoalitionTable(allContributions []ContributionSet) coalitionTable {
+	table := newEmptyCoalitionTable(GetAllTouchpoints(allContributions))
+	table.addContributions(allContributions)
+	table.zetaTransform()
+
+	return table
+}
+
+// newEmptyCoalitionTable interns the given touchpoints and allocates a table of zero values for all their coalitions.
+func newEmptyCoalitionTable(touchpoints Touchpoints) coalitionTable {
+	indices := make(map[Touchpoint]uint, len(touchpoints))
+	for index, touchpoint := range touchpoints {
+		indices[touchpoint] = uint(index)
+	}
+
+	return coalitionTable{
+		touchpoints: touchpoints,
+		indices:     indices,
+		values:      make([]big.Float, 1<<uint(len(touchpoints))),
+	}
+}
+
+// addContributions adds the value of each contribution at the bitmask of its touchpoints.
+func (table coalitionTable) addContributions(allContributions []ContributionSet) {
+	for _, contribution := range allContributions {
+		mask, _ := table.getMask(contribution.Touchpoints)
+		table.values[mask].Add(&table.values[mask], &contribution.Value)
+	}
+}
+
+// getMask returns the bitmask representing the given coalition.
+// If the coalition contains touchpoints unknown to the table, they are ignored and false is returned in the second
+// coordinate.
+func (table coalitionTable) getMask(coalition map[Touchpoint]struct{}) (uint, bool) {
+	mask := uint(0)
+	known := true
+	for touchpoint := range coalition {
+		index, found := table.indices[touchpoint]
+		if !found {
+			known = false
+			continue
+		}
+		mask |= 1 << index
+	}
+	return mask, known
+}
+
+// getCoalition returns the coalition represented by the given bitmask.
+func (table coalitionTable) getCoalition(mask uint) map[Touchpoint]struct{} {
+	coalition := make(map[Touchpoint]struct{}, bits.OnesCount(mask))
+	for index, touchpoint := range table.touchpoints {
+		if mask&(1<<uint(index)) > 0 {
+			coalition[touchpoint] = struct{}{}
+		}
+	}
+	return coalition
+}
+
+// zetaTransform replaces every entry of the table with the sum of the entries of all its subsets.
+func (table coalitionTable) zetaTransform() {
+	for index := range table.touchpoints {
+		bit := uint(1) << uint(index)
+		for mask := range table.values {
+			if uint(mask)&bit > 0 {
+				table.values[mask].Add(&table.values[mask], &table.values[uint(mask)^bit])
+			}
+		}
+	}
+}
+
+// mobiusTransform replaces every entry of the table with its Harsanyi dividend, inverting zetaTransform.
+func (table coalitionTable) mobiusTransform() {
+	for index := range table.touchpoints {
+		bit := uint(1) << uint(index)
+		for mask := range table.values {
+			if uint(mask)&bit > 0 {
+				table.values[mask].Sub(&table.values[mask], &table.values[uint(mask)^bit])
+			}
+		}
+	}
+}
+
+// getSemivalue returns the weighted sum of the marginal contributions of the touchpoint interned at the given index to
+// all coalitions, where weights[size] is the weight of coalitions with the given size.
+// With the weights of getShapleyWeights, this is the Shapley value; with those of getBanzhafWeights, it is the Banzhaf
+// value.
+func (table coalitionTable) getSemivalue(index uint, weights []big.Float) big.Float {
+	semivalue := new(big.Float)
+	bit := uint(1) << index
+
+	for mask := range table.values {
+		if uint(mask)&bit > 0 {
+			continue
+		}
+		addedCoalitionValue := new(big.Float).Sub(&table.values[uint(mask)|bit], &table.values[mask])
+		addedSemivalue := new(big.Float).Mul(&weights[bits.OnesCount(uint(mask))], addedCoalitionValue)
+		semivalue.Add(semivalue, addedSemivalue)
+	}
+
+	return *semivalue
+}
+
+// getShapleyWeights returns the weights size! * (n - size - 1)! / n! of the marginal contributions to coalitions of
+// all sizes 0, .., n - 1 in a game with n players.
+func getShapleyWeights(numberTouchpoints int) []big.Float {
+	weights := make([]big.Float, numberTouchpoints)
+	denominator := new(big.Float).SetInt(new(big.Int).MulRange(1, int64(numberTouchpoints)))
+
+	for size := range weights {
+		nominator := new(big.Int).MulRange(1, int64(size))
+		nominator.Mul(nominator, new(big.Int).MulRange(1, int64(numberTouchpoints-size-1)))
+		weights[size].Quo(new(big.Float).SetInt(nominator), denominator)
+	}
+
+	return weights
+}
+
+// getBanzhafWeights returns the weight 1 / 2^(n - 1) of the marginal contributions to coalitions of all sizes
+// 0, .., n - 1 in a game with n players.
+func getBanzhafWeights(numberTouchpoints int) []big.Float {
+	weights := make([]big.Float, numberTouchpoints)
+	for size := range weights {
+		weights[size].SetMantExp(big.NewFloat(1), -(numberTouchpoints - 1))
+	}
+	return weights
+}
@@ -0,0 +1,44 @@
+package attribution
+
+import (
+	"testing"
+)
+
+func TestNewCoalitionTable(t *testing.T) {
+	contributions := contributionSetFixture()
+	table := newCoalitionTable(contributions)
+
+	if len(table.values) != 1<<uint(len(GetAllTouchpoints(contributions))) {
+		t.Fatalf("got %d coalitions want %d", len(table.values), 1<<uint(len(GetAllTouchpoints(contributions))))
+	}
+	for mask := range table.values {
+		coalition := make(map[Touchpoint]struct{})
+		for index, touchpoint := range table.touchpoints {
+			if mask&(1<<uint(index)) > 0 {
+				coalition[touchpoint] = struct{}{}
+			}
+		}
+		want := GetCoalitionValue(coalition, contributions)
+
+		if table.values[mask].Cmp(&want) != 0 {
+			t.Errorf("%v: got %s want %s", coalition, table.values[mask].String(), want.String())
+		}
+	}
+}
+
+func TestCoalitionTableGetMask(t *testing.T) {
+	table := newCoalitionTable(contributionSetFixture())
+
+	mask, known := table.getMask(coalitionFixture())
+	if !known {
+		t.Errorf("coalition contains unknown touchpoints")
+	}
+	if mask != 1<<1|1<<2 {
+		t.Errorf("got %b want %b", mask, 1<<1|1<<2)
+	}
+
+	_, known = table.getMask(map[Touchpoint]struct{}{Touchpoint{"unknown"}: struct{}{}})
+	if known {
+		t.Errorf("coalition contains no unknown touchpoints")
+	}
+}
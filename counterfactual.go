@@ -0,0 +1,166 @@
+package attribution
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// A ConversionModel predicts the probability that a journey exposed to a given path of touchpoints converts, such as
+// a LogisticRegression or an externally trained propensity model.
+type ConversionModel interface {
+	// GetConversionProbability returns the predicted conversion probability of the given path, which may be empty.
+	GetConversionProbability(touchpoints Touchpoints) float64
+}
+
+// ConversionModelFunc adapts an ordinary function to the ConversionModel interface.
+type ConversionModelFunc func(touchpoints Touchpoints) float64
+
+// GetConversionProbability calls the function.
+func (function ConversionModelFunc) GetConversionProbability(touchpoints Touchpoints) float64 {
+	return function(touchpoints)
+}
+
+// GetCounterfactualValues splits the value of every contribution among its distinct touchpoints in proportion to their
+// removal effects under the given conversion model, i.e. the drop in predicted conversion probability when all
+// occurrences of the touchpoint are removed from the path.
+// Touchpoints whose removal doesn't lower the probability receive no credit; if no touchpoint's removal does, the
+// value is split evenly. The values add up to the total value of all contributions with at least one touchpoint.
+func GetCounterfactualValues(model ConversionModel, allContributions []Contribution) AttributionResult {
+	values := newCounterfactualResult(allContributions)
+
+	for _, contribution := range AggregateContributions(allContributions) {
+		if len(contribution.Touchpoints) == 0 || contribution.Value.Sign() == 0 {
+			continue
+		}
+
+		touchpoints := GetAllTouchpoints([]ContributionSet{contribution.Set()})
+		probability := model.GetConversionProbability(contribution.Touchpoints)
+		effects := make([]big.Float, len(touchpoints))
+		for index, touchpoint := range touchpoints {
+			remaining := filterTouchpoints(contribution.Touchpoints, func(other Touchpoint) bool {
+				return other != touchpoint
+			})
+			// negative effects are ignored by addProportionalValues
+			effects[index].SetFloat64(probability - model.GetConversionProbability(remaining))
+		}
+
+		addProportionalValues(values, &contribution.Value, touchpoints, effects)
+	}
+
+	return values
+}
+
+// GetCounterfactualValuesChecked is like GetCounterfactualValues, but returns an error for empty or non-finite input,
+// for invalid counts, for a missing model and for predictions that aren't probabilities.
+func GetCounterfactualValuesChecked(model ConversionModel, allContributions []Contribution) (AttributionResult, error) {
+	if err := validateConversionModel(model, allContributions); err != nil {
+		return nil, err
+	}
+	checkedModel := &checkedConversionModel{model: model}
+	values := GetCounterfactualValues(checkedModel, allContributions)
+	if checkedModel.err != nil {
+		return nil, checkedModel.err
+	}
+	return values, nil
+}
+
+// GetCounterfactualShapleyValues splits the value of every contribution among its distinct touchpoints in proportion
+// to their Shapley values in the game in which a coalition achieves the predicted conversion probability of the path
+// restricted to its touchpoints, keeping their order and repetitions.
+// As for GetCounterfactualValues, touchpoints with negative Shapley values receive no credit, and if no touchpoint's
+// Shapley value is positive, the value is split evenly. The values add up to the total value of all contributions with
+// at least one touchpoint.
+// The runtime grows exponentially in the number of distinct touchpoints per contribution, but not in the total number
+// of touchpoints.
+func GetCounterfactualShapleyValues(model ConversionModel, allContributions []Contribution) AttributionResult {
+	values := newCounterfactualResult(allContributions)
+
+	for _, contribution := range AggregateContributions(allContributions) {
+		if len(contribution.Touchpoints) == 0 || contribution.Value.Sign() == 0 {
+			continue
+		}
+
+		table := newEmptyCoalitionTable(GetAllTouchpoints([]ContributionSet{contribution.Set()}))
+		for mask := range table.values {
+			coalition := table.getCoalition(uint(mask))
+			path := filterTouchpoints(contribution.Touchpoints, func(touchpoint Touchpoint) bool {
+				_, found := coalition[touchpoint]
+				return found
+			})
+			table.values[mask].SetFloat64(model.GetConversionProbability(path))
+		}
+
+		weights := getShapleyWeights(len(table.touchpoints))
+		credits := make([]big.Float, len(table.touchpoints))
+		for index := range table.touchpoints {
+			credits[index] = table.getSemivalue(uint(index), weights)
+		}
+
+		addProportionalValues(values, &contribution.Value, table.touchpoints, credits)
+	}
+
+	return values
+}
+
+// GetCounterfactualShapleyValuesChecked is like GetCounterfactualShapleyValues, but returns an error for empty or
+// non-finite input, for invalid counts, for a missing model and for predictions that aren't probabilities.
+func GetCounterfactualShapleyValuesChecked(model ConversionModel, allContributions []Contribution) (AttributionResult, error) {
+	if err := validateConversionModel(model, allContributions); err != nil {
+		return nil, err
+	}
+	checkedModel := &checkedConversionModel{model: model}
+	values := GetCounterfactualShapleyValues(checkedModel, allContributions)
+	if checkedModel.err != nil {
+		return nil, checkedModel.err
+	}
+	return values, nil
+}
+
+// newCounterfactualResult returns a result holding a value of zero for every touchpoint of the given contributions.
+func newCounterfactualResult(allContributions []Contribution) AttributionResult {
+	values := make(AttributionResult)
+	for _, touchpoint := range GetAllTouchpoints(getContributionSets(allContributions)) {
+		values[touchpoint] = big.Float{}
+	}
+	return values
+}
+
+// filterTouchpoints returns the touchpoints of the given path for which keep returns true, in their original order.
+func filterTouchpoints(touchpoints Touchpoints, keep func(touchpoint Touchpoint) bool) Touchpoints {
+	filtered := Touchpoints{}
+	for _, touchpoint := range touchpoints {
+		if keep(touchpoint) {
+			filtered = append(filtered, touchpoint)
+		}
+	}
+	return filtered
+}
+
+// validateConversionModel checks the given contributions and that a model is given.
+func validateConversionModel(model ConversionModel, allContributions []Contribution) error {
+	if err := validateContributions(allContributions); err != nil {
+		return err
+	}
+	if model == nil {
+		return fmt.Errorf("%w: missing conversion model", ErrInvalidParameter)
+	}
+	return nil
+}
+
+// A checkedConversionModel wraps a conversion model and records the first prediction that isn't a probability,
+// replacing it with zero so that the attribution can finish without panicking.
+type checkedConversionModel struct {
+	model ConversionModel
+	err   error
+}
+
+func (checked *checkedConversionModel) GetConversionProbability(touchpoints Touchpoints) float64 {
+	probability := checked.model.GetConversionProbability(touchpoints)
+	if !(probability >= 0 && probability <= 1) {
+		if checked.err == nil {
+			checked.err = fmt.Errorf("%w: conversion probability %g of path %s", ErrInvalidPrediction, probability, touchpoints)
+		}
+		return 0
+	}
+	return probability
+}
@@ -0,0 +1,170 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func ExampleGetCounterfactualShapleyValues() {
+	// only the last touchpoint of a path drives conversions
+	model := ConversionModelFunc(func(touchpoints Touchpoints) float64 {
+		if len(touchpoints) == 0 {
+			return 0.01
+		}
+		return map[string]float64{"search": 0.2, "display": 0.05}[touchpoints[len(touchpoints)-1].Name]
+	})
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: Touchpoints{Touchpoint{"display"}, Touchpoint{"search"}},
+			Value:       *new(big.Float).SetFloat64(100.),
+		},
+		Contribution{
+			Touchpoints: Touchpoints{Touchpoint{"search"}},
+			Value:       *new(big.Float).SetFloat64(40.),
+		},
+	}
+	removalValues := GetCounterfactualValues(model, contributions)
+	shapleyValues := GetCounterfactualShapleyValues(model, contributions)
+
+	for _, touchpoint := range shapleyValues.GetTouchpoints() {
+		removalValue := removalValues[touchpoint]
+		shapleyValue := shapleyValues[touchpoint]
+		fmt.Println(touchpoint.Name, removalValue.Text('f', 2), shapleyValue.Text('f', 2))
+	}
+	// Output:
+	// display 0.00 10.53
+	// search 140.00 129.47
+}
+
+// additiveModel predicts a base probability plus a lift for every distinct touchpoint of a path.
+func additiveModel(touchpoints Touchpoints) float64 {
+	lifts := map[Touchpoint]float64{}
+	for index, touchpoint := range touchpointFixture() {
+		lifts[touchpoint] = 0.01 * float64(index+1)
+	}
+
+	probability := 0.01
+	seen := make(map[Touchpoint]struct{})
+	for _, touchpoint := range touchpoints {
+		if _, found := seen[touchpoint]; !found {
+			seen[touchpoint] = struct{}{}
+			probability += lifts[touchpoint]
+		}
+	}
+	return probability
+}
+
+func TestGetCounterfactualValuesAdditive(t *testing.T) {
+	contributions := contributionFixture()
+	model := ConversionModelFunc(additiveModel)
+	removalValues := GetCounterfactualValues(model, contributions)
+	shapleyValues := GetCounterfactualShapleyValues(model, contributions)
+
+	// without interactions, removal effects and Shapley values coincide with the lifts
+	for _, touchpoint := range GetAllTouchpoints(getContributionSets(contributions)) {
+		removalValue := removalValues[touchpoint]
+		shapleyValue := shapleyValues[touchpoint]
+
+		got, _ := shapleyValue.Float64()
+		want, _ := removalValue.Float64()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: got %f want %f", touchpoint, got, want)
+		}
+	}
+
+	var nonEmptyContributions []ContributionSet
+	for _, contribution := range getContributionSets(contributions) {
+		if len(contribution.Touchpoints) > 0 {
+			nonEmptyContributions = append(nonEmptyContributions, contribution)
+		}
+	}
+	if err := shapleyValues.CheckEfficiency(nonEmptyContributions, 1e-6); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetCounterfactualShapleyValuesNegativeCredit(t *testing.T) {
+	// exposure to both touchpoints converts worse than to either one alone, so b lowers the conversion probability
+	model := ConversionModelFunc(func(touchpoints Touchpoints) float64 {
+		return map[string]float64{"": 0, "{a}": 0.5, "{b}": 0.1, "{a} {b}": 0.001}[strings.Trim(touchpoints.String(), "[]")]
+	})
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: Touchpoints{Touchpoint{"a"}, Touchpoint{"b"}},
+			Value:       *new(big.Float).SetFloat64(100.),
+		},
+	}
+
+	cases := []struct {
+		attribute func(ConversionModel, []Contribution) (AttributionResult, error)
+		want      map[string]float64
+	}{
+		// removing either touchpoint raises the conversion probability, so the value is split evenly
+		{GetCounterfactualValuesChecked, map[string]float64{"a": 50., "b": 50.}},
+		{GetCounterfactualShapleyValuesChecked, map[string]float64{"a": 100., "b": 0.}},
+	}
+	for index, c := range cases {
+		values, err := c.attribute(model, contributions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, want := range c.want {
+			value := values[Touchpoint{name}]
+			if got, _ := value.Float64(); math.Abs(got-want) > 1e-9 {
+				t.Errorf("case %d: %s: got %f want %f", index, name, got, want)
+			}
+		}
+	}
+}
+
+func TestLogisticRegressionIsConversionModel(t *testing.T) {
+	contributions := logisticFixture()
+	var model ConversionModel = NewLogisticRegression(contributions, LogisticRegressionOptions{})
+
+	values := GetCounterfactualValues(model, contributions)
+	expectedValues := GetLogisticRegressionValues(contributions, LogisticRegressionOptions{})
+	for touchpoint, value := range values {
+		expectedValue := expectedValues[touchpoint]
+		if value.Cmp(&expectedValue) != 0 {
+			t.Errorf("%s: got %s want %s", touchpoint.Name, value.String(), expectedValue.String())
+		}
+	}
+}
+
+func TestGetCounterfactualValuesChecked(t *testing.T) {
+	contributions := contributionFixture()
+	invalidModel := ConversionModelFunc(func(touchpoints Touchpoints) float64 {
+		return 1.5
+	})
+	// only the paths with touchpoints removed, down to the empty path, get invalid predictions
+	invalidSubPathModel := ConversionModelFunc(func(touchpoints Touchpoints) float64 {
+		if len(touchpoints) == 0 {
+			return math.NaN()
+		}
+		return additiveModel(touchpoints)
+	})
+
+	for _, shapley := range []bool{false, true} {
+		attribute := GetCounterfactualValuesChecked
+		if shapley {
+			attribute = GetCounterfactualShapleyValuesChecked
+		}
+
+		if _, err := attribute(ConversionModelFunc(additiveModel), nil); !errors.Is(err, ErrEmptyInput) {
+			t.Errorf("got %v want ErrEmptyInput", err)
+		}
+		if _, err := attribute(nil, contributions); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("got %v want ErrInvalidParameter", err)
+		}
+		if _, err := attribute(invalidModel, contributions); !errors.Is(err, ErrInvalidPrediction) {
+			t.Errorf("got %v want ErrInvalidPrediction", err)
+		}
+		if _, err := attribute(invalidSubPathModel, contributions); !errors.Is(err, ErrInvalidPrediction) {
+			t.Errorf("got %v want ErrInvalidPrediction", err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package attribution
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVOptions configures how ReadContributionsCSV parses channel-path exports.
+type CSVOptions struct {
+	PathColumn        string // name of the column holding the path; defaults to "path"
+	Separator         string // separator between touchpoints of a path; defaults to ">"
+	ValueColumn       string // name of the column holding the value; defaults to "value"
+	JourneysColumn    string // name of the column holding the number of journeys; optional
+	ConversionsColumn string // name of the column holding the number of conversions; optional
+	Comma             rune   // field delimiter; defaults to ','
+}
+
+// A CSVError represents a malformed row of a CSV file.
+type CSVError struct {
+	Line int // line of the row, counting the header as line 1
+	Err  error
+}
+
+func (err *CSVError) Error() string {
+	return fmt.Sprintf("line %d: %s", err.Line, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *CSVError) Unwrap() error {
+	return err.Err
+}
+
+// ReadContributionsCSV parses a CSV file of channel paths such as "Search > Display > Email" into contributions.
+// The first row must be a header naming the columns. Touchpoint names are trimmed and lower-cased.
+// Rows with empty touchpoint names, unparsable numbers or a wrong number of fields are reported as a *CSVError
+// wrapping ErrMalformedRow.
+// Only the value column of the options is read; use ReadContributionsCSVColumns for exports with several value columns.
+func ReadContributionsCSV(reader io.Reader, options CSVOptions) ([]Contribution, error) {
+	if options.ValueColumn == "" {
+		options.ValueColumn = "value"
+	}
+	allContributions, err := ReadContributionsCSVColumns(reader, options, []string{options.ValueColumn})
+	if err != nil {
+		return nil, err
+	}
+	return allContributions[0], nil
+}
+
+// ReadContributionsCSVColumns is like ReadContributionsCSV, but reads every given value column instead of the value
+// column of the options, returning one slice of contributions per value column in the given order.
+// The slices share their paths, journeys and conversions, but not the underlying touchpoint slices.
+func ReadContributionsCSVColumns(reader io.Reader, options CSVOptions, valueColumns []string) ([][]Contribution, error) {
+	if options.PathColumn == "" {
+		options.PathColumn = "path"
+	}
+	if options.Separator == "" {
+		options.Separator = ">"
+	}
+	if len(valueColumns) == 0 {
+		return nil, fmt.Errorf("%w: no value columns", ErrInvalidParameter)
+	}
+
+	csvReader := csv.NewReader(reader)
+	if options.Comma != 0 {
+		csvReader.Comma = options.Comma
+	}
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, &CSVError{Line: 1, Err: fmt.Errorf("%w: missing header", ErrMalformedRow)}
+	}
+	if err != nil {
+		return nil, wrapCSVError(err, 1)
+	}
+	columns := make(map[string]int, len(header))
+	for index, name := range header {
+		columns[strings.TrimSpace(name)] = index
+	}
+	getColumn := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		index, found := columns[name]
+		if !found {
+			return -1, &CSVError{Line: 1, Err: fmt.Errorf("%w: missing column %q", ErrMalformedRow, name)}
+		}
+		return index, nil
+	}
+	pathColumn, err := getColumn(options.PathColumn)
+	if err != nil {
+		return nil, err
+	}
+	valueIndices := make([]int, len(valueColumns))
+	for index, name := range valueColumns {
+		if name == "" {
+			return nil, fmt.Errorf("%w: empty value column name", ErrInvalidParameter)
+		}
+		if valueIndices[index], err = getColumn(name); err != nil {
+			return nil, err
+		}
+	}
+	journeysColumn, err := getColumn(options.JourneysColumn)
+	if err != nil {
+		return nil, err
+	}
+	conversionsColumn, err := getColumn(options.ConversionsColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	allContributions := make([][]Contribution, len(valueColumns))
+	for line := 2; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, wrapCSVError(err, line)
+		}
+
+		touchpoints, err := parsePath(record[pathColumn], options.Separator)
+		if err != nil {
+			return nil, &CSVError{Line: line, Err: err}
+		}
+		contribution := Contribution{Touchpoints: touchpoints}
+		if journeysColumn >= 0 {
+			if contribution.Journeys, err = parseCount(record[journeysColumn]); err != nil {
+				return nil, &CSVError{Line: line, Err: err}
+			}
+		}
+		if conversionsColumn >= 0 {
+			if contribution.Conversions, err = parseCount(record[conversionsColumn]); err != nil {
+				return nil, &CSVError{Line: line, Err: err}
+			}
+		}
+		for index, valueIndex := range valueIndices {
+			columnContribution := contribution
+			if index > 0 {
+				columnContribution.Touchpoints = append(Touchpoints(nil), touchpoints...)
+			}
+			if _, ok := columnContribution.Value.SetString(strings.TrimSpace(record[valueIndex])); !ok {
+				return nil, &CSVError{Line: line, Err: fmt.Errorf("%w: invalid value %q", ErrMalformedRow, record[valueIndex])}
+			}
+			allContributions[index] = append(allContributions[index], columnContribution)
+		}
+	}
+
+	return allContributions, nil
+}
+
+// wrapCSVError converts errors of the csv package into a *CSVError wrapping ErrMalformedRow.
+func wrapCSVError(err error, line int) error {
+	var parseError *csv.ParseError
+	if errors.As(err, &parseError) {
+		return &CSVError{Line: parseError.Line, Err: fmt.Errorf("%w: %s", ErrMalformedRow, parseError.Err)}
+	}
+	return &CSVError{Line: line, Err: err}
+}
+
+// parsePath splits a path such as "Search > Display" into its touchpoints, trimming and lower-casing their names.
+func parsePath(path string, separator string) (Touchpoints, error) {
+	rawTouchpoints := strings.Split(path, separator)
+	touchpoints := make(Touchpoints, len(rawTouchpoints))
+	for index, rawTouchpoint := range rawTouchpoints {
+		name := strings.ToLower(strings.TrimSpace(rawTouchpoint))
+		if name == "" {
+			return nil, fmt.Errorf("%w: empty touchpoint at position %d of path %q", ErrMalformedRow, index+1, path)
+		}
+		touchpoints[index] = Touchpoint{Name: name}
+	}
+	return touchpoints, nil
+}
+
+// parseCount parses a non-negative number of journeys or conversions.
+func parseCount(raw string) (int64, error) {
+	count, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("%w: invalid count %q", ErrMalformedRow, raw)
+	}
+	return count, nil
+}
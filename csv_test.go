@@ -0,0 +1,121 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleReadContributionsCSV() {
+	export := `path,gmv,journeys,transactions
+Google CPC > Display > Email,250.5,10,2
+ Display ,100,5,1
+`
+	contributions, err := ReadContributionsCSV(strings.NewReader(export), CSVOptions{
+		ValueColumn:       "gmv",
+		JourneysColumn:    "journeys",
+		ConversionsColumn: "transactions",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, contribution := range contributions {
+		fmt.Println(contribution, contribution.GetJourneys(), contribution.GetConversions())
+	}
+	// Output:
+	// {[{google cpc} {display} {email}] 250.5} 10 2
+	// {[{display}] 100} 5 1
+}
+
+func TestReadContributionsCSV(t *testing.T) {
+	export := "channels;value\nsearch | email;1\nemail;2\n"
+
+	contributions, err := ReadContributionsCSV(strings.NewReader(export), CSVOptions{
+		PathColumn: "channels",
+		Separator:  "|",
+		Comma:      ';',
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(contributions) != 2 {
+		t.Fatalf("got %d contributions want 2", len(contributions))
+	}
+	if got := contributions[0].Touchpoints.String(); got != "[{search} {email}]" {
+		t.Errorf("got %s want [{search} {email}]", got)
+	}
+	if got := contributions[1].Value.String(); got != "2" {
+		t.Errorf("got %s want 2", got)
+	}
+}
+
+func TestReadContributionsCSVColumns(t *testing.T) {
+	export := "path,gmv,margin,journeys\nsearch > email,100,20,3\nemail,50,5,1\n"
+
+	options := CSVOptions{JourneysColumn: "journeys"}
+	allContributions, err := ReadContributionsCSVColumns(strings.NewReader(export), options, []string{"margin", "gmv"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(allContributions) != 2 {
+		t.Fatalf("got %d value columns want 2", len(allContributions))
+	}
+	for index, want := range []string{"{[{search} {email}] 20} 3|{[{email}] 5} 1", "{[{search} {email}] 100} 3|{[{email}] 50} 1"} {
+		var got []string
+		for _, contribution := range allContributions[index] {
+			got = append(got, fmt.Sprintf("%s %d", contribution, contribution.GetJourneys()))
+		}
+		if strings.Join(got, "|") != want {
+			t.Errorf("column %d: got %s want %s", index, strings.Join(got, "|"), want)
+		}
+	}
+	allContributions[0][0].Touchpoints[0] = Touchpoint{"display"}
+	if got := allContributions[1][0].Touchpoints[0]; got != (Touchpoint{"search"}) {
+		t.Errorf("got %s want {search}", got)
+	}
+
+	if _, err := ReadContributionsCSVColumns(strings.NewReader(export), CSVOptions{}, nil); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("got %v want %v", err, ErrInvalidParameter)
+	}
+	if _, err := ReadContributionsCSVColumns(strings.NewReader(export), CSVOptions{}, []string{"gmv", "value"}); !errors.Is(err, ErrMalformedRow) {
+		t.Errorf("got %v want %v", err, ErrMalformedRow)
+	}
+}
+
+func TestReadContributionsCSVMalformed(t *testing.T) {
+	cases := []struct {
+		name   string
+		export string
+		line   int
+	}{
+		{"missing header", "", 1},
+		{"missing column", "path\na\n", 1},
+		{"empty touchpoint", "path,value\na,1\na > > b,1\n", 3},
+		{"empty path", "path,value\n,1\n", 2},
+		{"invalid value", "path,value\na,1\nb,x\n", 3},
+		{"wrong field count", "path,value\na,1,2\n", 2},
+	}
+
+	for _, c := range cases {
+		_, err := ReadContributionsCSV(strings.NewReader(c.export), CSVOptions{})
+		if !errors.Is(err, ErrMalformedRow) {
+			t.Errorf("%s: got %v want %v", c.name, err, ErrMalformedRow)
+			continue
+		}
+		var csvError *CSVError
+		if !errors.As(err, &csvError) {
+			t.Errorf("%s: got %T want *CSVError", c.name, err)
+			continue
+		}
+		if csvError.Line != c.line {
+			t.Errorf("%s: got line %d want %d", c.name, csvError.Line, c.line)
+		}
+	}
+
+	_, err := ReadContributionsCSV(strings.NewReader("path,value,n\na,1,-1\n"), CSVOptions{JourneysColumn: "n"})
+	if !errors.Is(err, ErrMalformedRow) {
+		t.Errorf("got %v want %v", err, ErrMalformedRow)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"time"
 )
 
 // A Touchpoint represents a contributing entity in a ContributionSet.
@@ -42,9 +43,16 @@ func (touchpoints Touchpoints) Swap(i, j int) {
 }
 
 // A Contribution consists of an ordered list of touchpoints together with their combined value.
+// Optionally, it carries the number of journeys that followed this path and how many of them converted. If both are
+// zero, the contribution represents a single converting journey.
+// Optionally, it also carries the time of every touchpoint occurrence and of the conversion.
 type Contribution struct {
-	Touchpoints Touchpoints
-	Value       big.Float
+	Touchpoints    Touchpoints
+	Value          big.Float
+	Journeys       int64       // number of journeys following this path
+	Conversions    int64       // number of journeys following this path that converted
+	Timestamps     []time.Time // Timestamps[i] is the time Touchpoints[i] occurred, if known
+	ConversionTime time.Time   // time of the conversion, if known
 }
 
 func (contribution Contribution) String() string {
@@ -61,15 +69,100 @@ func (contribution Contribution) Set() ContributionSet {
 	return ContributionSet{
 		Touchpoints: touchpoints,
 		Value:       contribution.Value,
+		Journeys:    contribution.Journeys,
+		Conversions: contribution.Conversions,
+	}
+}
+
+// HasTimestamps reports whether the time of every touchpoint occurrence is known.
+func (contribution Contribution) HasTimestamps() bool {
+	if len(contribution.Timestamps) != len(contribution.Touchpoints) {
+		return false
+	}
+	for _, timestamp := range contribution.Timestamps {
+		if timestamp.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// GetConversionTime returns the time of the conversion.
+// If it isn't known, the time of the last touchpoint occurrence is returned instead.
+func (contribution Contribution) GetConversionTime() time.Time {
+	if !contribution.ConversionTime.IsZero() || len(contribution.Timestamps) == 0 {
+		return contribution.ConversionTime
+	}
+	conversionTime := contribution.Timestamps[0]
+	for _, timestamp := range contribution.Timestamps[1:] {
+		if timestamp.After(conversionTime) {
+			conversionTime = timestamp
+		}
 	}
+	return conversionTime
+}
+
+// GetJourneys returns the number of journeys represented by the contribution.
+func (contribution Contribution) GetJourneys() int64 {
+	return getJourneys(contribution.Journeys, contribution.Conversions)
+}
+
+// GetConversions returns the number of converting journeys represented by the contribution.
+func (contribution Contribution) GetConversions() int64 {
+	return getConversions(contribution.Journeys, contribution.Conversions)
+}
+
+// GetConversionRate returns the share of converting journeys represented by the contribution.
+func (contribution Contribution) GetConversionRate() float64 {
+	return float64(contribution.GetConversions()) / float64(contribution.GetJourneys())
 }
 
 // A ContributionSet consists of an unordered set of touchpoints together with their combined value.
+// Journeys and conversions are interpreted as for a Contribution.
 type ContributionSet struct {
 	Touchpoints map[Touchpoint]struct{}
 	Value       big.Float
+	Journeys    int64 // number of journeys with this set of touchpoints
+	Conversions int64 // number of journeys with this set of touchpoints that converted
 }
 
 func (contribution ContributionSet) String() string {
 	return fmt.Sprintf("{%s %s}", contribution.Touchpoints, contribution.Value.String())
 }
+
+// GetJourneys returns the number of journeys represented by the contribution.
+func (contribution ContributionSet) GetJourneys() int64 {
+	return getJourneys(contribution.Journeys, contribution.Conversions)
+}
+
+// GetConversions returns the number of converting journeys represented by the contribution.
+func (contribution ContributionSet) GetConversions() int64 {
+	return getConversions(contribution.Journeys, contribution.Conversions)
+}
+
+// GetConversionRate returns the share of converting journeys represented by the contribution.
+func (contribution ContributionSet) GetConversionRate() float64 {
+	return float64(contribution.GetConversions()) / float64(contribution.GetJourneys())
+}
+
+// getConversions returns the number of converting journeys given the raw counts of a contribution.
+// A contribution without any counts represents a single converting journey.
+func getConversions(journeys int64, conversions int64) int64 {
+	if journeys <= 0 && conversions <= 0 {
+		return 1
+	}
+	if conversions < 0 {
+		return 0
+	}
+	return conversions
+}
+
+// getJourneys returns the number of journeys given the raw counts of a contribution.
+// There are never fewer journeys than converting journeys.
+func getJourneys(journeys int64, conversions int64) int64 {
+	conversions = getConversions(journeys, conversions)
+	if journeys < conversions {
+		return conversions
+	}
+	return journeys
+}
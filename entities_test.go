@@ -0,0 +1,78 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func ExampleContribution_GetConversionRate() {
+	contribution := Contribution{
+		Touchpoints: Touchpoints([]Touchpoint{
+			Touchpoint{"Touchpoint 1"},
+			Touchpoint{"Touchpoint 2"},
+		}),
+		Value:       *(new(big.Float).SetFloat64(100.)),
+		Journeys:    40,
+		Conversions: 10,
+	}
+
+	fmt.Println(contribution.GetJourneys(), contribution.GetConversions(), contribution.GetConversionRate())
+	// Output: 40 10 0.25
+}
+
+func TestGetJourneysAndConversions(t *testing.T) {
+	cases := []struct {
+		journeys        int64
+		conversions     int64
+		wantJourneys    int64
+		wantConversions int64
+	}{
+		{0, 0, 1, 1},
+		{5, 0, 5, 0},
+		{5, 2, 5, 2},
+		{0, 3, 3, 3},
+		{2, 3, 3, 3},
+	}
+
+	for _, c := range cases {
+		contribution := ContributionSet{Journeys: c.journeys, Conversions: c.conversions}
+		if got := contribution.GetJourneys(); got != c.wantJourneys {
+			t.Errorf("(%d, %d): got %d journeys want %d", c.journeys, c.conversions, got, c.wantJourneys)
+		}
+		if got := contribution.GetConversions(); got != c.wantConversions {
+			t.Errorf("(%d, %d): got %d conversions want %d", c.journeys, c.conversions, got, c.wantConversions)
+		}
+	}
+}
+
+func TestSetKeepsCounts(t *testing.T) {
+	contribution := Contribution{
+		Touchpoints: touchpointFixture(),
+		Value:       *(new(big.Float).SetFloat64(100.)),
+		Journeys:    7,
+		Conversions: 3,
+	}
+
+	got := contribution.Set()
+	if got.Journeys != 7 || got.Conversions != 3 {
+		t.Errorf("got (%d, %d) want (7, 3)", got.Journeys, got.Conversions)
+	}
+}
+
+func TestValidateCounts(t *testing.T) {
+	contributions := contributionFixture()
+	contributions[3].Journeys = 2
+	contributions[3].Conversions = 5
+
+	if err := validateContributions(contributions); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("got %v want %v", err, ErrInvalidCount)
+	}
+
+	contributions[3].Journeys = -1
+	contributions[3].Conversions = 0
+	if err := validateContributions(contributions); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("got %v want %v", err, ErrInvalidCount)
+	}
+}
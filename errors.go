@@ -0,0 +1,130 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnknownTouchpoint is returned if a touchpoint doesn't occur in any of the provided contributions.
+	ErrUnknownTouchpoint = errors.New("attribution: unknown touchpoint")
+	// ErrEmptyInput is returned if no contributions are provided.
+	ErrEmptyInput = errors.New("attribution: empty input")
+	// ErrNonFiniteValue is returned if a contribution's value is infinite or can't be represented as a float64 where
+	// required.
+	ErrNonFiniteValue = errors.New("attribution: non-finite value")
+	// ErrInvalidCount is returned if a contribution has negative journeys or conversions, or more conversions than
+	// journeys.
+	ErrInvalidCount = errors.New("attribution: invalid count")
+	// ErrInvalidTimestamps is returned if a contribution lacks timestamps for some of its touchpoints or if a touchpoint
+	// occurred after the conversion.
+	ErrInvalidTimestamps = errors.New("attribution: invalid timestamps")
+	// ErrInvalidParameter is returned if a model parameter is out of its valid range.
+	ErrInvalidParameter = errors.New("attribution: invalid parameter")
+	// ErrMalformedRow is returned if a row of an input file can't be parsed.
+	ErrMalformedRow = errors.New("attribution: malformed row")
+	// ErrInvalidConfig is returned if a configuration file can't be parsed.
+	ErrInvalidConfig = errors.New("attribution: invalid config")
+	// ErrInvalidPrediction is returned if a ConversionModel predicts a conversion probability outside of [0, 1].
+	ErrInvalidPrediction = errors.New("attribution: invalid prediction")
+	// ErrNotConverged is returned if a model can't be fitted to the given contributions.
+	ErrNotConverged = errors.New("attribution: fit didn't converge")
+	// ErrInefficientResult is returned if the values of an AttributionResult don't add up to the total value of the
+	// attributed contributions.
+	ErrInefficientResult = errors.New("attribution: attributed values don't add up to total value")
+	// ErrUnknownModel is returned if no model is registered under a given name.
+	ErrUnknownModel = errors.New("attribution: unknown model")
+	// ErrDuplicateModel is returned if a model is registered under a name that is already taken.
+	ErrDuplicateModel = errors.New("attribution: duplicate model")
+)
+
+// validateContributions checks that the given contributions are non-empty and only carry finite values.
+func validateContributions(allContributions []Contribution) error {
+	if len(allContributions) == 0 {
+		return ErrEmptyInput
+	}
+	for index, contribution := range allContributions {
+		if contribution.Value.IsInf() {
+			return fmt.Errorf("%w: contribution %d has value %s", ErrNonFiniteValue, index, contribution.Value.String())
+		}
+		if err := validateCounts(index, contribution.Journeys, contribution.Conversions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateContributionSets checks that the given contributions are non-empty and only carry finite values.
+func validateContributionSets(allContributions []ContributionSet) error {
+	if len(allContributions) == 0 {
+		return ErrEmptyInput
+	}
+	for index, contribution := range allContributions {
+		if contribution.Value.IsInf() {
+			return fmt.Errorf("%w: contribution %d has value %s", ErrNonFiniteValue, index, contribution.Value.String())
+		}
+		if err := validateCounts(index, contribution.Journeys, contribution.Conversions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCounts checks that the journeys and conversions of the contribution with the given index are consistent.
+func validateCounts(index int, journeys int64, conversions int64) error {
+	if journeys < 0 || conversions < 0 || (journeys > 0 && conversions > journeys) {
+		return fmt.Errorf("%w: contribution %d has %d journeys and %d conversions", ErrInvalidCount, index, journeys, conversions)
+	}
+	return nil
+}
+
+// validateTimestamps checks that all given contributions carry a timestamp for every touchpoint occurrence, none of
+// which lies after the conversion.
+func validateTimestamps(allContributions []Contribution) error {
+	for index, contribution := range allContributions {
+		if !contribution.HasTimestamps() {
+			return fmt.Errorf("%w: contribution %d lacks timestamps", ErrInvalidTimestamps, index)
+		}
+		conversionTime := contribution.GetConversionTime()
+		for _, timestamp := range contribution.Timestamps {
+			if timestamp.After(conversionTime) {
+				return fmt.Errorf("%w: contribution %d has a touchpoint after its conversion", ErrInvalidTimestamps, index)
+			}
+		}
+	}
+	return nil
+}
+
+// validateGroups checks that no touchpoint is assigned to more than one of the given groups.
+func validateGroups(groups []Touchpoints) error {
+	assigned := make(map[Touchpoint]int)
+	for index, group := range groups {
+		for _, touchpoint := range group {
+			if other, found := assigned[touchpoint]; found && other != index {
+				return fmt.Errorf("%w: touchpoint %s is assigned to groups %d and %d", ErrInvalidParameter, touchpoint.Name, other, index)
+			}
+			assigned[touchpoint] = index
+		}
+	}
+	return nil
+}
+
+// validateTouchpoint checks that the given touchpoint occurs in at least one of the given contributions.
+func validateTouchpoint(touchpoint Touchpoint, allContributions []Contribution) error {
+	for _, contribution := range allContributions {
+		if _, found := findTouchpoint(touchpoint, contribution.Touchpoints); found {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownTouchpoint, touchpoint.Name)
+}
+
+// validateSetTouchpoint checks that the given touchpoint occurs in at least one of the given contributions.
+func validateSetTouchpoint(touchpoint Touchpoint, allContributions []ContributionSet) error {
+	for _, contribution := range allContributions {
+		if _, found := contribution.Touchpoints[touchpoint]; found {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownTouchpoint, touchpoint.Name)
+}
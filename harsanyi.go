@@ -0,0 +1,103 @@
+package attribution
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// A HarsanyiDividend is the value a coalition creates on top of the dividends of all its proper subcoalitions.
+// The value of every coalition is the sum of the dividends of its subcoalitions, so positive dividends of coalitions
+// with several touchpoints quantify synergies between them, and negative ones redundancies.
+type HarsanyiDividend struct {
+	Coalition map[Touchpoint]struct{}
+	Value     big.Float
+}
+
+func (dividend HarsanyiDividend) String() string {
+	touchpoints := make(Touchpoints, 0, len(dividend.Coalition))
+	for touchpoint := range dividend.Coalition {
+		touchpoints = append(touchpoints, touchpoint)
+	}
+	sort.Sort(touchpoints)
+	return fmt.Sprintf("{%s %s}", touchpoints, dividend.Value.String())
+}
+
+// harsanyiDivider is implemented by characteristic functions which compute their dividends faster than by a Möbius
+// transform of all coalition values.
+type harsanyiDivider interface {
+	getHarsanyiDividends() []HarsanyiDividend
+}
+
+// GetHarsanyiDividends returns the non-zero Harsanyi dividends of the game defined by GetCoalitionValue.
+// In this game, the dividend of a coalition is the total value of all contributions with exactly its touchpoints, so
+// the dividends are computed in time linear in the total length of all contributions.
+// Dividends are ordered by the first occurrence of their coalition.
+func GetHarsanyiDividends(allContributions []ContributionSet) []HarsanyiDividend {
+	var dividends []HarsanyiDividend
+	for _, contribution := range AggregateContributionSets(allContributions) {
+		if contribution.Value.Sign() != 0 {
+			dividends = append(dividends, HarsanyiDividend{Coalition: contribution.Touchpoints, Value: contribution.Value})
+		}
+	}
+	return dividends
+}
+
+// GetHarsanyiDividendsChecked is like GetHarsanyiDividends, but returns an error for empty or non-finite input.
+func GetHarsanyiDividendsChecked(allContributions []ContributionSet) ([]HarsanyiDividend, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return nil, err
+	}
+	return GetHarsanyiDividends(allContributions), nil
+}
+
+// GetHarsanyiDividendsWith returns the non-zero Harsanyi dividends of the game defined by the given characteristic
+// function.
+// Unless the function provides its dividends directly, they are obtained by a Möbius transform of the values of all
+// coalitions, whose runtime grows exponentially in the number of touchpoints.
+func GetHarsanyiDividendsWith(function CharacteristicFunction) []HarsanyiDividend {
+	if divider, ok := function.(harsanyiDivider); ok {
+		return divider.getHarsanyiDividends()
+	}
+
+	table := newCharacteristicTable(function)
+	table.mobiusTransform()
+
+	var dividends []HarsanyiDividend
+	for mask := range table.values {
+		if table.values[mask].Sign() != 0 {
+			dividends = append(dividends, HarsanyiDividend{
+				Coalition: table.getCoalition(uint(mask)),
+				Value:     table.values[mask],
+			})
+		}
+	}
+	return dividends
+}
+
+// GetShapleyValuesFromDividends returns the Shapley values of all touchpoints occurring in the given dividends, which
+// result from splitting the dividend of every coalition evenly among its members.
+// The dividend of the empty coalition is ignored.
+func GetShapleyValuesFromDividends(dividends []HarsanyiDividend) AttributionResult {
+	shapleyValues := make(AttributionResult)
+	for _, dividend := range dividends {
+		if len(dividend.Coalition) == 0 {
+			continue
+		}
+		share := new(big.Float).Quo(&dividend.Value, new(big.Float).SetInt64(int64(len(dividend.Coalition))))
+		for touchpoint := range dividend.Coalition {
+			shapleyValue := shapleyValues[touchpoint]
+			shapleyValue.Add(&shapleyValue, share)
+			shapleyValues[touchpoint] = shapleyValue
+		}
+	}
+	return shapleyValues
+}
+
+func (function ContainedValue) getHarsanyiDividends() []HarsanyiDividend {
+	return GetHarsanyiDividends(function)
+}
+
+func (function ConversionRateValue) getHarsanyiDividends() []HarsanyiDividend {
+	return GetHarsanyiDividends(function.getConversionRates())
+}
@@ -0,0 +1,155 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func ExampleGetHarsanyiDividendsWith() {
+	experiment := `coalition,lift
+search,100
+display,50
+display > search,200
+`
+	function, err := ReadCoalitionValuesCSV(strings.NewReader(experiment), CSVOptions{
+		PathColumn:  "coalition",
+		ValueColumn: "lift",
+	})
+	if err != nil {
+		panic(err)
+	}
+	dividends := GetHarsanyiDividendsWith(function)
+	shapleyValues := GetShapleyValuesFromDividends(dividends)
+
+	for _, dividend := range dividends {
+		fmt.Println(dividend)
+	}
+	for _, touchpoint := range shapleyValues.GetTouchpoints() {
+		shapleyValue := shapleyValues[touchpoint]
+		fmt.Println(touchpoint.Name, shapleyValue.String())
+	}
+	// Output:
+	// {[{display}] 50}
+	// {[{search}] 100}
+	// {[{display} {search}] 50}
+	// display 75
+	// search 125
+}
+
+func TestGetHarsanyiDividends(t *testing.T) {
+	contributions := contributionSetFixture()
+	dividends := GetHarsanyiDividends(contributions)
+	table := newCoalitionTable(contributions)
+
+	// summing the dividends of all subcoalitions recovers the characteristic function
+	for mask := range table.values {
+		coalition := table.getCoalition(uint(mask))
+		value := new(big.Float)
+		for _, dividend := range dividends {
+			contained := true
+			for touchpoint := range dividend.Coalition {
+				if _, found := coalition[touchpoint]; !found {
+					contained = false
+					break
+				}
+			}
+			if contained {
+				value.Add(value, &dividend.Value)
+			}
+		}
+
+		got, _ := value.Float64()
+		want, _ := table.values[mask].Float64()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("coalition %v: got %f want %f", coalition, got, want)
+		}
+	}
+}
+
+func TestGetHarsanyiDividendsWith(t *testing.T) {
+	contributions := contributionSetFixture()
+	contributions[3].Journeys = 10
+	contributions[3].Conversions = 4
+
+	for _, function := range []CharacteristicFunction{
+		ContainedValue(contributions),
+		IntersectingValue(contributions),
+		ConversionRateValue(contributions),
+	} {
+		shapleyValues := GetShapleyValuesFromDividends(GetHarsanyiDividendsWith(function))
+		// evaluate the function coalition by coalition without taking any shortcut
+		expectedValues := GetShapleyValuesWith(evaluatedFunction{function})
+
+		for _, touchpoint := range function.GetTouchpoints() {
+			shapleyValue := shapleyValues[touchpoint]
+			expectedValue := expectedValues[touchpoint]
+
+			got, _ := shapleyValue.Float64()
+			want, _ := expectedValue.Float64()
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("%T: %s: got %f want %f", function, touchpoint, got, want)
+			}
+		}
+	}
+}
+
+func TestGetHarsanyiDividendsWithRepeated(t *testing.T) {
+	a, b := Touchpoint{"a"}, Touchpoint{"b"}
+	var function CoalitionValues
+	function.Set(map[Touchpoint]struct{}{a: struct{}{}}, *big.NewFloat(1))
+	function.Set(map[Touchpoint]struct{}{b: struct{}{}}, *big.NewFloat(2))
+	function.Set(map[Touchpoint]struct{}{a: struct{}{}, b: struct{}{}}, *big.NewFloat(3.0000000001))
+
+	// transforming the coalition values must leave the function's own values untouched
+	for call := 1; call <= 2; call++ {
+		var synergy *HarsanyiDividend
+		dividends := GetHarsanyiDividendsWith(&function)
+		for index := range dividends {
+			if len(dividends[index].Coalition) == 2 {
+				synergy = &dividends[index]
+			}
+		}
+		if synergy == nil {
+			t.Fatalf("call %d: missing dividend of {a, b}", call)
+		}
+		if got, _ := synergy.Value.Float64(); math.Abs(got-1e-10) > 1e-15 {
+			t.Errorf("call %d: got %g want %g", call, got, 1e-10)
+		}
+		value := function.GetValue(map[Touchpoint]struct{}{a: struct{}{}, b: struct{}{}})
+		if got, _ := value.Float64(); got != 3.0000000001 {
+			t.Errorf("call %d: got %g want %g", call, got, 3.0000000001)
+		}
+	}
+}
+
+func TestGetShapleyValuesLargeGame(t *testing.T) {
+	// far too many touchpoints for a table of all coalitions
+	var contributions []ContributionSet
+	for i := 0; i < 100; i++ {
+		contributions = append(contributions, ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{fmt.Sprintf("Touchpoint %d", i)}:   struct{}{},
+				Touchpoint{fmt.Sprintf("Touchpoint %d", i+1)}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(10.),
+		})
+	}
+
+	shapleyValues := GetShapleyValues(contributions)
+	if len(shapleyValues) != 101 {
+		t.Errorf("got %d touchpoints want 101", len(shapleyValues))
+	}
+	if err := shapleyValues.CheckEfficiency(contributions, 1e-9); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetHarsanyiDividendsChecked(t *testing.T) {
+	if _, err := GetHarsanyiDividendsChecked(nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("got %v want ErrEmptyInput", err)
+	}
+}
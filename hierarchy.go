@@ -0,0 +1,175 @@
+package attribution
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// HierarchySeparator separates the levels of a hierarchical touchpoint name such as "paid search|brand|ad 1".
+const HierarchySeparator = "|"
+
+// NewHierarchicalTouchpoint returns the touchpoint with the given hierarchy path, starting at the top level.
+func NewHierarchicalTouchpoint(levels ...string) Touchpoint {
+	return Touchpoint{Name: strings.Join(levels, HierarchySeparator)}
+}
+
+// GetLevels returns the hierarchy path of the touchpoint, starting at the top level.
+// Touchpoints without HierarchySeparator in their name have a single level.
+func (touchpoint Touchpoint) GetLevels() []string {
+	return strings.Split(touchpoint.Name, HierarchySeparator)
+}
+
+// GetDepth returns the number of levels of the touchpoint.
+func (touchpoint Touchpoint) GetDepth() int {
+	return strings.Count(touchpoint.Name, HierarchySeparator) + 1
+}
+
+// AtLevel returns the ancestor of the touchpoint at the given depth, where depth 1 is the top level.
+// Touchpoints at or above the given depth are returned unchanged. Depths less than 1 are treated as 1.
+func (touchpoint Touchpoint) AtLevel(depth int) Touchpoint {
+	if depth < 1 {
+		depth = 1
+	}
+	levels := touchpoint.GetLevels()
+	if depth >= len(levels) {
+		return touchpoint
+	}
+	return NewHierarchicalTouchpoint(levels[:depth]...)
+}
+
+// GroupByLevel partitions the given touchpoints by their ancestors at the given depth.
+// Groups are ordered by the first occurrence of their ancestor.
+func GroupByLevel(touchpoints Touchpoints, depth int) []Touchpoints {
+	indices := make(map[Touchpoint]int)
+	var groups []Touchpoints
+	for _, touchpoint := range touchpoints {
+		ancestor := touchpoint.AtLevel(depth)
+		index, found := indices[ancestor]
+		if !found {
+			index = len(groups)
+			indices[ancestor] = index
+			groups = append(groups, nil)
+		}
+		groups[index] = append(groups[index], touchpoint)
+	}
+	return groups
+}
+
+// A LevelRule rewrites hierarchical touchpoints to their ancestor at the given depth.
+type LevelRule int
+
+// Apply rewrites the given name to its ancestor at the rule's depth. It applies to all names.
+func (rule LevelRule) Apply(name string) (string, bool) {
+	return Touchpoint{Name: name}.AtLevel(int(rule)).Name, true
+}
+
+// RollUpContributions returns copies of the given contributions with all touchpoints replaced by their ancestors at the
+// given depth.
+func RollUpContributions(allContributions []Contribution, depth int) []Contribution {
+	return Normalizer{Rules: []NormalizationRule{LevelRule(depth)}}.NormalizeContributions(allContributions)
+}
+
+// RollUpContributionSets returns copies of the given contributions with all touchpoints replaced by their ancestors at
+// the given depth.
+func RollUpContributionSets(allContributions []ContributionSet, depth int) []ContributionSet {
+	return Normalizer{Rules: []NormalizationRule{LevelRule(depth)}}.NormalizeContributionSets(allContributions)
+}
+
+// RollUp sums the values of all touchpoints sharing the same ancestor at the given depth.
+func (result AttributionResult) RollUp(depth int) AttributionResult {
+	rolledUp := make(AttributionResult)
+	// sum in a fixed order to obtain reproducible results
+	for _, touchpoint := range result.GetTouchpoints() {
+		ancestor := touchpoint.AtLevel(depth)
+		value := result[touchpoint]
+		sum := rolledUp[ancestor]
+		sum.Add(&sum, &value)
+		rolledUp[ancestor] = sum
+	}
+	return rolledUp
+}
+
+// DrillDown distributes the value of every touchpoint of the result among its descendants in the given finer result,
+// in proportion to their values there. Thus, the returned values roll up to the result exactly.
+// If the values of all descendants of a touchpoint sum to zero, its value is split evenly among them. Touchpoints
+// without descendants in the finer result keep their value.
+func (result AttributionResult) DrillDown(finer AttributionResult) AttributionResult {
+	children := make(map[Touchpoint]Touchpoints)
+	for _, touchpoint := range finer.GetTouchpoints() {
+		if parent, found := result.findAncestor(touchpoint); found {
+			children[parent] = append(children[parent], touchpoint)
+		}
+	}
+
+	drilledDown := make(AttributionResult, len(finer))
+	for parent, parentValue := range result {
+		parentChildren, found := children[parent]
+		if !found {
+			drilledDown[parent] = parentValue
+			continue
+		}
+
+		childrenTotal := new(big.Float)
+		for _, child := range parentChildren {
+			childValue := finer[child]
+			childrenTotal.Add(childrenTotal, &childValue)
+		}
+		for _, child := range parentChildren {
+			value := new(big.Float)
+			if childrenTotal.Sign() != 0 {
+				childValue := finer[child]
+				value.Mul(&parentValue, &childValue)
+				value.Quo(value, childrenTotal)
+			} else {
+				value.Quo(&parentValue, new(big.Float).SetInt64(int64(len(parentChildren))))
+			}
+			drilledDown[child] = *value
+		}
+	}
+
+	return drilledDown
+}
+
+// findAncestor returns the closest proper ancestor of the given touchpoint in the result, or the touchpoint itself if
+// it is part of the result.
+func (result AttributionResult) findAncestor(touchpoint Touchpoint) (Touchpoint, bool) {
+	for depth := touchpoint.GetDepth(); depth > 0; depth-- {
+		ancestor := touchpoint.AtLevel(depth)
+		if _, found := result[ancestor]; found {
+			return ancestor, true
+		}
+	}
+	return Touchpoint{}, false
+}
+
+// AttributeAtLevel runs the model on the given contributions rolled up to the given depth.
+func AttributeAtLevel(model Model, allContributions []Contribution, depth int) (AttributionResult, error) {
+	if depth < 1 {
+		return nil, fmt.Errorf("%w: depth %d is less than 1", ErrInvalidParameter, depth)
+	}
+	return model.Attribute(RollUpContributions(allContributions, depth))
+}
+
+// AttributeHierarchy runs the model on the given contributions at every depth from 1 to maxDepth and drills each
+// result down from the one above, so that the value of every touchpoint equals the summed values of its children.
+// The i-th result holds the values at depth i + 1.
+func AttributeHierarchy(model Model, allContributions []Contribution, maxDepth int) ([]AttributionResult, error) {
+	if maxDepth < 1 {
+		return nil, fmt.Errorf("%w: depth %d is less than 1", ErrInvalidParameter, maxDepth)
+	}
+
+	results := make([]AttributionResult, maxDepth)
+	for depth := 1; depth <= maxDepth; depth++ {
+		result, err := AttributeAtLevel(model, allContributions, depth)
+		if err != nil {
+			return nil, err
+		}
+		if depth > 1 {
+			result = results[depth-2].DrillDown(result)
+		}
+		results[depth-1] = result
+	}
+
+	return results, nil
+}
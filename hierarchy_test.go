@@ -0,0 +1,146 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func hierarchyFixture() []Contribution {
+	return []Contribution{
+		Contribution{
+			Touchpoints: Touchpoints{
+				NewHierarchicalTouchpoint("search", "brand"),
+				NewHierarchicalTouchpoint("display", "retargeting"),
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+		Contribution{
+			Touchpoints: Touchpoints{NewHierarchicalTouchpoint("search", "generic")},
+			Value:       *new(big.Float).SetFloat64(50.),
+		},
+		Contribution{
+			Touchpoints: Touchpoints{
+				NewHierarchicalTouchpoint("display", "prospecting"),
+				NewHierarchicalTouchpoint("search", "generic"),
+			},
+			Value: *new(big.Float).SetFloat64(80.),
+		},
+		Contribution{
+			Touchpoints: Touchpoints{Touchpoint{"email"}},
+			Value:       *new(big.Float).SetFloat64(20.),
+		},
+	}
+}
+
+func ExampleAttributeHierarchy() {
+	results, err := AttributeHierarchy(ShapleyModel{}, hierarchyFixture(), 2)
+	if err != nil {
+		panic(err)
+	}
+
+	for depth, result := range results {
+		for _, touchpoint := range result.GetTouchpoints() {
+			value := result[touchpoint]
+			fmt.Printf("%d %s %s\n", depth+1, touchpoint.Name, value.Text('f', 2))
+		}
+	}
+	// Output:
+	// 1 display 90.00
+	// 1 email 20.00
+	// 1 search 140.00
+	// 2 display|prospecting 40.00
+	// 2 display|retargeting 50.00
+	// 2 email 20.00
+	// 2 search|brand 50.00
+	// 2 search|generic 90.00
+}
+
+func TestTouchpointLevels(t *testing.T) {
+	touchpoint := NewHierarchicalTouchpoint("search", "brand", "ad 1")
+
+	if touchpoint.Name != "search|brand|ad 1" {
+		t.Errorf("got name %q", touchpoint.Name)
+	}
+	if touchpoint.GetDepth() != 3 {
+		t.Errorf("got depth %d want 3", touchpoint.GetDepth())
+	}
+	for depth, want := range map[int]string{-1: "search", 1: "search", 2: "search|brand", 3: "search|brand|ad 1", 4: "search|brand|ad 1"} {
+		if got := touchpoint.AtLevel(depth).Name; got != want {
+			t.Errorf("depth %d: got %q want %q", depth, got, want)
+		}
+	}
+}
+
+func TestAttributeHierarchyReconciles(t *testing.T) {
+	contributions := hierarchyFixture()
+	contributions[1].Touchpoints = append(contributions[1].Touchpoints, NewHierarchicalTouchpoint("display", "retargeting"))
+
+	for _, model := range []Model{ShapleyModel{}, LinearModel{}, MarkovModel{Order: 1}} {
+		results, err := AttributeHierarchy(model, contributions, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rolledUp := results[1].RollUp(1)
+		for touchpoint, want := range results[0] {
+			got := rolledUp[touchpoint]
+			if difference, _ := new(big.Float).Sub(&got, &want).Float64(); difference > 1e-9 || difference < -1e-9 {
+				t.Errorf("%s: %s rolls up to %s want %s", model.Name(), touchpoint.Name, got.String(), want.String())
+			}
+		}
+	}
+}
+
+func TestDrillDownWithoutChildren(t *testing.T) {
+	coarse := AttributionResult{
+		Touchpoint{"search"}:  *new(big.Float).SetFloat64(10.),
+		Touchpoint{"display"}: *new(big.Float).SetFloat64(6.),
+	}
+	fine := AttributionResult{
+		NewHierarchicalTouchpoint("search", "brand"):   *new(big.Float).SetFloat64(0.),
+		NewHierarchicalTouchpoint("search", "generic"): *new(big.Float).SetFloat64(0.),
+	}
+
+	drilledDown := coarse.DrillDown(fine)
+	for name, want := range map[string]float64{"search|brand": 5., "search|generic": 5., "display": 6.} {
+		value := drilledDown[Touchpoint{name}]
+		if got, _ := value.Float64(); got != want {
+			t.Errorf("%s: got %f want %f", name, got, want)
+		}
+	}
+}
+
+func TestOwenModelGroupsByTopLevel(t *testing.T) {
+	contributions := hierarchyFixture()
+	contributionSets := getContributionSets(contributions)
+	touchpoints := GetAllTouchpoints(contributionSets)
+
+	groups := GroupByLevel(touchpoints, 1)
+	if len(groups) != 3 {
+		t.Fatalf("got %d groups want 3", len(groups))
+	}
+
+	result, err := OwenModel{}.Attribute(contributions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := GetOwenValues(contributionSets, groups)
+	for _, touchpoint := range touchpoints {
+		got := result[touchpoint]
+		expected := want[touchpoint]
+		if got.Cmp(&expected) != 0 {
+			t.Errorf("%s: got %s want %s", touchpoint.Name, got.String(), expected.String())
+		}
+	}
+}
+
+func TestAttributeAtLevelInvalidDepth(t *testing.T) {
+	if _, err := AttributeAtLevel(LinearModel{}, hierarchyFixture(), 0); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("got %v want ErrInvalidParameter", err)
+	}
+	if _, err := AttributeHierarchy(LinearModel{}, hierarchyFixture(), 0); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("got %v want ErrInvalidParameter", err)
+	}
+}
@@ -0,0 +1,299 @@
+package attribution
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// maxNDJSONLineLength is the maximal length of a single line read by an NDJSONReader.
+const maxNDJSONLineLength = 64 * 1024 * 1024
+
+// MarshalJSON encodes a touchpoint as its name.
+func (touchpoint Touchpoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(touchpoint.Name)
+}
+
+// UnmarshalJSON decodes a touchpoint from its name.
+func (touchpoint *Touchpoint) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &touchpoint.Name)
+}
+
+// MarshalJSON encodes touchpoints as a list of names.
+func (touchpoints Touchpoints) MarshalJSON() ([]byte, error) {
+	if touchpoints == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]Touchpoint(touchpoints))
+}
+
+// jsonContribution is the JSON representation of a Contribution and a ContributionSet.
+// Values are encoded as exact decimal strings to preserve them without rounding.
+type jsonContribution struct {
+	Touchpoints    Touchpoints `json:"touchpoints"`
+	Value          string      `json:"value"`
+	Journeys       int64       `json:"journeys,omitempty"`
+	Conversions    int64       `json:"conversions,omitempty"`
+	Timestamps     []time.Time `json:"timestamps,omitempty"`
+	ConversionTime *time.Time  `json:"conversion_time,omitempty"`
+}
+
+// MarshalJSON encodes a contribution as a JSON object with its value as decimal string.
+func (contribution Contribution) MarshalJSON() ([]byte, error) {
+	encoded := jsonContribution{
+		Touchpoints: contribution.Touchpoints,
+		Value:       getValueString(&contribution.Value),
+		Journeys:    contribution.Journeys,
+		Conversions: contribution.Conversions,
+		Timestamps:  contribution.Timestamps,
+	}
+	if !contribution.ConversionTime.IsZero() {
+		encoded.ConversionTime = &contribution.ConversionTime
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes a contribution encoded by MarshalJSON.
+func (contribution *Contribution) UnmarshalJSON(data []byte) error {
+	var decoded jsonContribution
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	value, err := parseValueString(decoded.Value)
+	if err != nil {
+		return err
+	}
+
+	*contribution = Contribution{
+		Touchpoints: decoded.Touchpoints,
+		Value:       value,
+		Journeys:    decoded.Journeys,
+		Conversions: decoded.Conversions,
+		Timestamps:  decoded.Timestamps,
+	}
+	if decoded.ConversionTime != nil {
+		contribution.ConversionTime = *decoded.ConversionTime
+	}
+	return nil
+}
+
+// MarshalJSON encodes a contribution as a JSON object with its touchpoints in sorted order and its value as decimal
+// string.
+func (contribution ContributionSet) MarshalJSON() ([]byte, error) {
+	touchpoints := make(Touchpoints, 0, len(contribution.Touchpoints))
+	for touchpoint := range contribution.Touchpoints {
+		touchpoints = append(touchpoints, touchpoint)
+	}
+	sort.Sort(touchpoints)
+
+	return json.Marshal(jsonContribution{
+		Touchpoints: touchpoints,
+		Value:       getValueString(&contribution.Value),
+		Journeys:    contribution.Journeys,
+		Conversions: contribution.Conversions,
+	})
+}
+
+// UnmarshalJSON decodes a contribution encoded by MarshalJSON.
+func (contribution *ContributionSet) UnmarshalJSON(data []byte) error {
+	var decoded jsonContribution
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	value, err := parseValueString(decoded.Value)
+	if err != nil {
+		return err
+	}
+
+	touchpoints := make(map[Touchpoint]struct{}, len(decoded.Touchpoints))
+	for _, touchpoint := range decoded.Touchpoints {
+		touchpoints[touchpoint] = struct{}{}
+	}
+	*contribution = ContributionSet{
+		Touchpoints: touchpoints,
+		Value:       value,
+		Journeys:    decoded.Journeys,
+		Conversions: decoded.Conversions,
+	}
+	return nil
+}
+
+// MarshalJSON encodes a result as a JSON object mapping touchpoint names to decimal strings.
+func (result AttributionResult) MarshalJSON() ([]byte, error) {
+	encoded := make(map[string]string, len(result))
+	for touchpoint, value := range result {
+		encoded[touchpoint.Name] = getValueString(&value)
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes a result encoded by MarshalJSON.
+func (result *AttributionResult) UnmarshalJSON(data []byte) error {
+	var decoded map[string]string
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*result = make(AttributionResult, len(decoded))
+	for name, rawValue := range decoded {
+		value, err := parseValueString(rawValue)
+		if err != nil {
+			return err
+		}
+		(*result)[Touchpoint{name}] = value
+	}
+	return nil
+}
+
+// defaultValuePrecision is the precision big.Float uses when parsing a value into a zero big.Float.
+const defaultValuePrecision = 64
+
+// getValueString returns the exact decimal representation of a value.
+// Every binary fraction has a finite decimal expansion, which parseValueString reads back without rounding regardless
+// of the value's precision.
+func getValueString(value *big.Float) string {
+	if value.IsInf() {
+		return value.Text('g', -1)
+	}
+	// a value with k bits after the binary point has exactly k digits after the decimal point
+	fractionalDigits := int(value.MinPrec()) - value.MantExp(nil)
+	if fractionalDigits < 0 {
+		fractionalDigits = 0
+	}
+	return value.Text('f', fractionalDigits)
+}
+
+// parseValueString parses a decimal representation of a value with enough precision to represent every value written
+// by getValueString exactly. Other representations are rounded to at least the default precision.
+func parseValueString(raw string) (big.Float, error) {
+	digits := 0
+	for _, char := range raw {
+		if char == 'e' || char == 'E' {
+			break
+		}
+		if char >= '0' && char <= '9' {
+			digits++
+		}
+	}
+	// a binary value written out exactly with d decimal digits has a mantissa below 10^d
+	precision := uint(math.Ceil(float64(digits) * math.Log2(10)))
+	if precision < defaultValuePrecision {
+		precision = defaultValuePrecision
+	}
+
+	var value big.Float
+	value.SetPrec(precision)
+	if _, ok := value.SetString(raw); !ok {
+		return big.Float{}, fmt.Errorf("%w: invalid value %q", ErrMalformedRow, raw)
+	}
+	return value, nil
+}
+
+// An NDJSONError represents a malformed line of a newline-delimited JSON stream.
+type NDJSONError struct {
+	Line int
+	Err  error
+}
+
+func (err *NDJSONError) Error() string {
+	return fmt.Sprintf("line %d: %s", err.Line, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *NDJSONError) Unwrap() error {
+	return err.Err
+}
+
+// An NDJSONWriter writes values as newline-delimited JSON, one value per line.
+type NDJSONWriter struct {
+	encoder *json.Encoder
+}
+
+// NewNDJSONWriter returns an NDJSONWriter writing to the given writer.
+func NewNDJSONWriter(writer io.Writer) *NDJSONWriter {
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	return &NDJSONWriter{encoder: encoder}
+}
+
+// Write writes a single value such as a Contribution, a ContributionSet or an AttributionResult on its own line.
+func (writer *NDJSONWriter) Write(value interface{}) error {
+	return writer.encoder.Encode(value)
+}
+
+// An NDJSONReader reads values from newline-delimited JSON, one value per line. Empty lines are skipped.
+type NDJSONReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewNDJSONReader returns an NDJSONReader reading from the given reader.
+func NewNDJSONReader(reader io.Reader) *NDJSONReader {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxNDJSONLineLength)
+	return &NDJSONReader{scanner: scanner}
+}
+
+// Read decodes the next line into the given value.
+// At the end of the stream, io.EOF is returned. Malformed lines are reported as an *NDJSONError wrapping
+// ErrMalformedRow.
+func (reader *NDJSONReader) Read(value interface{}) error {
+	for reader.scanner.Scan() {
+		reader.line++
+		data := bytes.TrimSpace(reader.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(data, value); err != nil {
+			return &NDJSONError{Line: reader.line, Err: fmt.Errorf("%w: %s", ErrMalformedRow, err)}
+		}
+		return nil
+	}
+	if err := reader.scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// ReadContribution decodes the next line into a Contribution.
+func (reader *NDJSONReader) ReadContribution() (Contribution, error) {
+	var contribution Contribution
+	err := reader.Read(&contribution)
+	return contribution, err
+}
+
+// ReadContributionSet decodes the next line into a ContributionSet.
+func (reader *NDJSONReader) ReadContributionSet() (ContributionSet, error) {
+	var contribution ContributionSet
+	err := reader.Read(&contribution)
+	return contribution, err
+}
+
+// ReadResult decodes the next line into an AttributionResult.
+func (reader *NDJSONReader) ReadResult() (AttributionResult, error) {
+	var result AttributionResult
+	err := reader.Read(&result)
+	return result, err
+}
+
+// ReadContributionsNDJSON reads all contributions of a newline-delimited JSON stream.
+func ReadContributionsNDJSON(reader io.Reader) ([]Contribution, error) {
+	ndjsonReader := NewNDJSONReader(reader)
+	var allContributions []Contribution
+	for {
+		contribution, err := ndjsonReader.ReadContribution()
+		if err == io.EOF {
+			return allContributions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		allContributions = append(allContributions, contribution)
+	}
+}
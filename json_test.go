@@ -0,0 +1,202 @@
+package attribution
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ExampleNDJSONWriter() {
+	writer := NewNDJSONWriter(os.Stdout)
+
+	contribution := Contribution{
+		Touchpoints: []Touchpoint{
+			Touchpoint{"Touchpoint 1"},
+			Touchpoint{"Touchpoint 2"},
+		},
+		Value:       *new(big.Float).SetFloat64(100.5),
+		Journeys:    3,
+		Conversions: 1,
+	}
+	if err := writer.Write(contribution); err != nil {
+		panic(err)
+	}
+	if err := writer.Write(contribution.Set()); err != nil {
+		panic(err)
+	}
+	if err := writer.Write(GetShapleyValues([]ContributionSet{contribution.Set()})); err != nil {
+		panic(err)
+	}
+	// Output:
+	// {"touchpoints":["Touchpoint 1","Touchpoint 2"],"value":"100.5","journeys":3,"conversions":1}
+	// {"touchpoints":["Touchpoint 1","Touchpoint 2"],"value":"100.5","journeys":3,"conversions":1}
+	// {"Touchpoint 1":"50.25","Touchpoint 2":"50.25"}
+}
+
+func TestContributionJSONRoundTrip(t *testing.T) {
+	conversionTime := time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC)
+	contribution := Contribution{
+		Touchpoints:    touchpointFixture()[:3],
+		Journeys:       5,
+		Conversions:    2,
+		Timestamps:     []time.Time{conversionTime.Add(-3 * time.Hour), conversionTime.Add(-2 * time.Hour), conversionTime.Add(-time.Hour)},
+		ConversionTime: conversionTime,
+	}
+	contribution.Value.SetString("12345678901234567890.123456789")
+
+	data, err := json.Marshal(contribution)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Contribution
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.Touchpoints.String() != contribution.Touchpoints.String() {
+		t.Errorf("got %s want %s", decoded.Touchpoints, contribution.Touchpoints)
+	}
+	if decoded.Value.Cmp(&contribution.Value) != 0 {
+		t.Errorf("got %s want %s", decoded.Value.String(), contribution.Value.String())
+	}
+	if decoded.Journeys != 5 || decoded.Conversions != 2 {
+		t.Errorf("got (%d, %d) want (5, 2)", decoded.Journeys, decoded.Conversions)
+	}
+	if !decoded.ConversionTime.Equal(conversionTime) || len(decoded.Timestamps) != 3 || !decoded.Timestamps[0].Equal(contribution.Timestamps[0]) {
+		t.Errorf("got %v %v want %v %v", decoded.Timestamps, decoded.ConversionTime, contribution.Timestamps, conversionTime)
+	}
+
+	reencoded, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(data, reencoded) {
+		t.Errorf("got %s want %s", reencoded, data)
+	}
+}
+
+func TestContributionJSONRoundTripPrecision(t *testing.T) {
+	highPrecision := new(big.Float).SetPrec(200)
+	highPrecision.SetString("0.1")
+	oneThird := new(big.Float).Quo(big.NewFloat(1), big.NewFloat(3))
+	values := []*big.Float{new(big.Float).SetFloat64(0.1), highPrecision, oneThird, new(big.Float).SetFloat64(-1e-300)}
+
+	for _, value := range values {
+		contribution := Contribution{Touchpoints: touchpointFixture()[:1]}
+		contribution.Value.Set(value)
+
+		data, err := json.Marshal(contribution)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var decoded Contribution
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decoded.Value.Cmp(&contribution.Value) != 0 {
+			t.Errorf("got %s want %s", decoded.Value.Text('g', 70), contribution.Value.Text('g', 70))
+		}
+
+		reencoded, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(data, reencoded) {
+			t.Errorf("got %s want %s", reencoded, data)
+		}
+	}
+}
+
+func TestContributionSetJSONRoundTrip(t *testing.T) {
+	for _, contribution := range contributionSetFixture() {
+		data, err := json.Marshal(contribution)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var decoded ContributionSet
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(decoded.Touchpoints) != len(contribution.Touchpoints) {
+			t.Errorf("got %v want %v", decoded.Touchpoints, contribution.Touchpoints)
+		}
+		for touchpoint := range contribution.Touchpoints {
+			if _, found := decoded.Touchpoints[touchpoint]; !found {
+				t.Errorf("decoded contribution is missing %s", touchpoint)
+			}
+		}
+		if decoded.Value.Cmp(&contribution.Value) != 0 {
+			t.Errorf("got %s want %s", decoded.Value.String(), contribution.Value.String())
+		}
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewNDJSONWriter(&buffer)
+	contributions := contributionFixture()
+	for _, contribution := range contributions {
+		if err := writer.Write(contribution); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	buffer.WriteString("\n")
+	result := GetShapleyValues(getContributionSets(contributions))
+	if err := writer.Write(result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reader := NewNDJSONReader(&buffer)
+	for index := range contributions {
+		contribution, err := reader.ReadContribution()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if contribution.String() != contributions[index].String() {
+			t.Errorf("got %s want %s", contribution, contributions[index])
+		}
+	}
+	decodedResult, err := reader.ReadResult()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for touchpoint, value := range result {
+		decodedValue := decodedResult[touchpoint]
+		if getValueString(&decodedValue) != getValueString(&value) {
+			t.Errorf("%s: got %s want %s", touchpoint, decodedValue.String(), value.String())
+		}
+	}
+	if _, err := reader.ReadContribution(); err != io.EOF {
+		t.Errorf("got %v want %v", err, io.EOF)
+	}
+}
+
+func TestReadContributionsNDJSONMalformed(t *testing.T) {
+	stream := `{"touchpoints":["a"],"value":"1"}
+{"touchpoints":["b"],"value":"x"}
+`
+	_, err := ReadContributionsNDJSON(strings.NewReader(stream))
+	if !errors.Is(err, ErrMalformedRow) {
+		t.Fatalf("got %v want %v", err, ErrMalformedRow)
+	}
+	var ndjsonError *NDJSONError
+	if !errors.As(err, &ndjsonError) || ndjsonError.Line != 2 {
+		t.Errorf("got %v want an error in line 2", err)
+	}
+
+	contributions, err := ReadContributionsNDJSON(strings.NewReader(`{"touchpoints":["a","b"],"value":"1.5"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fmt.Sprint(contributions); got != "[{[{a} {b}] 1.5}]" {
+		t.Errorf("got %s want [{[{a} {b}] 1.5}]", got)
+	}
+}
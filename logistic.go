@@ -0,0 +1,352 @@
+package attribution
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// Defaults of LogisticRegressionOptions.
+const (
+	defaultLogisticIterations = 100
+	defaultLogisticTolerance  = 1e-8
+)
+
+// LogisticRegressionOptions configures NewLogisticRegression.
+type LogisticRegressionOptions struct {
+	Regularization float64 // weight of the L2 penalty on all coefficients except the intercept; zero disables it
+	Counts         bool    // use the number of occurrences of every touchpoint as feature instead of its presence
+	MaxIterations  int     // maximal number of Newton steps; defaults to 100
+	Tolerance      float64 // fitting stops once no coefficient changes by more than this; defaults to 1e-8
+}
+
+// A LogisticRegression predicts the probability that a journey converts from the touchpoints it was exposed to.
+// The log-odds of converting are the intercept plus the sum of the coefficients of all touchpoints, each multiplied by
+// its feature, i.e. one if the touchpoint is present (or the number of its occurrences if Counts is set) and zero
+// otherwise.
+type LogisticRegression struct {
+	Touchpoints    Touchpoints // touchpoints in sorted order
+	Counts         bool        // whether features are numbers of occurrences instead of presence
+	Intercept      float64
+	Coefficients   []float64 // Coefficients[i] belongs to Touchpoints[i]
+	StandardErrors []float64 // StandardErrors[i] is the standard error of Coefficients[i]; NaN if unavailable
+	LogLikelihood  float64   // log-likelihood of the training journeys under the fitted model, without penalty
+	Iterations     int       // number of Newton steps taken
+	Converged      bool      // whether the coefficients converged within the maximal number of iterations
+}
+
+// A CoefficientDiagnostic summarizes the fitted coefficient of a single touchpoint of a LogisticRegression.
+type CoefficientDiagnostic struct {
+	Touchpoint    Touchpoint
+	Coefficient   float64
+	StandardError float64
+	ZScore        float64 // coefficient divided by its standard error
+	OddsRatio     float64 // factor by which a unit increase of the feature multiplies the odds of converting
+}
+
+// A logisticFeature represents the non-zero feature of the touchpoint with the given index.
+type logisticFeature struct {
+	index int
+	value float64
+}
+
+// logisticSample represents a distinct path with its features and outcome.
+type logisticSample struct {
+	features    []logisticFeature // ordered by index, so that sums are reproducible
+	journeys    float64
+	conversions float64
+}
+
+// NewLogisticRegression fits a logistic regression on the journeys of the given contributions by Newton's method
+// (iteratively reweighted least squares), maximizing the L2-penalized log-likelihood.
+// Every contribution contributes its conversions as positive and its remaining journeys as negative examples, so that
+// non-converting journeys inform the fit. Contributions without journeys and conversions count as a single converting
+// journey. If no journey failed to convert, the intercept grows without bound and the fit doesn't converge.
+func NewLogisticRegression(allContributions []Contribution, options LogisticRegressionOptions) LogisticRegression {
+	if options.MaxIterations <= 0 {
+		options.MaxIterations = defaultLogisticIterations
+	}
+	if options.Tolerance <= 0 {
+		options.Tolerance = defaultLogisticTolerance
+	}
+
+	model := LogisticRegression{
+		Touchpoints: GetAllTouchpoints(getContributionSets(allContributions)),
+		Counts:      options.Counts,
+	}
+	indices := make(map[Touchpoint]int, len(model.Touchpoints))
+	for index, touchpoint := range model.Touchpoints {
+		indices[touchpoint] = index
+	}
+	var samples []logisticSample
+	for _, contribution := range AggregateContributions(allContributions) {
+		samples = append(samples, logisticSample{
+			features:    model.getFeatures(contribution.Touchpoints, indices),
+			journeys:    float64(contribution.GetJourneys()),
+			conversions: float64(contribution.GetConversions()),
+		})
+	}
+
+	// the intercept is the last parameter
+	size := len(model.Touchpoints) + 1
+	parameters := make([]float64, size)
+	var hessian [][]float64
+	for model.Iterations < options.MaxIterations {
+		var gradient []float64
+		gradient, hessian = getLogisticDerivatives(samples, parameters, options.Regularization)
+		step, ok := solveLinearSystem(copyMatrix(hessian), gradient)
+		if !ok {
+			break
+		}
+		model.Iterations++
+
+		maxChange := 0.
+		for index := range parameters {
+			parameters[index] += step[index]
+			maxChange = math.Max(maxChange, math.Abs(step[index]))
+		}
+		if maxChange <= options.Tolerance {
+			model.Converged = true
+			break
+		}
+	}
+
+	model.Intercept = parameters[size-1]
+	model.Coefficients = append([]float64(nil), parameters[:size-1]...)
+	_, hessian = getLogisticDerivatives(samples, parameters, options.Regularization)
+	model.StandardErrors = getStandardErrors(hessian)[:size-1]
+	for _, sample := range samples {
+		logOdds := model.getLogOdds(sample.features)
+		model.LogLikelihood += sample.conversions*getLogSigmoid(logOdds) +
+			(sample.journeys-sample.conversions)*getLogSigmoid(-logOdds)
+	}
+
+	return model
+}
+
+// getFeatures returns the non-zero features of the given touchpoints, ordered by index.
+// Touchpoints missing from indices are ignored.
+func (model LogisticRegression) getFeatures(touchpoints Touchpoints, indices map[Touchpoint]int) []logisticFeature {
+	values := make(map[int]float64)
+	for _, touchpoint := range touchpoints {
+		index, found := indices[touchpoint]
+		if !found {
+			continue
+		}
+		if model.Counts {
+			values[index]++
+		} else {
+			values[index] = 1
+		}
+	}
+
+	features := make([]logisticFeature, 0, len(values))
+	for index, value := range values {
+		features = append(features, logisticFeature{index: index, value: value})
+	}
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].index < features[j].index
+	})
+	return features
+}
+
+// getLogOdds returns the predicted log-odds of converting for the given features.
+func (model LogisticRegression) getLogOdds(features []logisticFeature) float64 {
+	logOdds := model.Intercept
+	for _, feature := range features {
+		logOdds += model.Coefficients[feature.index] * feature.value
+	}
+	return logOdds
+}
+
+// GetConversionProbability returns the predicted probability that a journey exposed to the given touchpoints
+// converts. Touchpoints unknown to the model are ignored.
+func (model LogisticRegression) GetConversionProbability(touchpoints Touchpoints) float64 {
+	indices := make(map[Touchpoint]int, len(model.Touchpoints))
+	for index, touchpoint := range model.Touchpoints {
+		indices[touchpoint] = index
+	}
+	return getSigmoid(model.getLogOdds(model.getFeatures(touchpoints, indices)))
+}
+
+// GetDiagnostics returns the diagnostics of the coefficients of all touchpoints, in the order of Touchpoints.
+func (model LogisticRegression) GetDiagnostics() []CoefficientDiagnostic {
+	diagnostics := make([]CoefficientDiagnostic, len(model.Touchpoints))
+	for index, touchpoint := range model.Touchpoints {
+		diagnostics[index] = CoefficientDiagnostic{
+			Touchpoint:    touchpoint,
+			Coefficient:   model.Coefficients[index],
+			StandardError: model.StandardErrors[index],
+			ZScore:        model.Coefficients[index] / model.StandardErrors[index],
+			OddsRatio:     math.Exp(model.Coefficients[index]),
+		}
+	}
+	return diagnostics
+}
+
+// getLogisticDerivatives returns the gradient and the negated Hessian of the penalized log-likelihood with respect to
+// the given parameters, whose last entry is the intercept.
+func getLogisticDerivatives(samples []logisticSample, parameters []float64, regularization float64) ([]float64, [][]float64) {
+	size := len(parameters)
+	intercept := size - 1
+	gradient := make([]float64, size)
+	hessian := make([][]float64, size)
+	for index := range hessian {
+		hessian[index] = make([]float64, size)
+	}
+
+	for _, sample := range samples {
+		logOdds := parameters[intercept]
+		for _, feature := range sample.features {
+			logOdds += parameters[feature.index] * feature.value
+		}
+		probability := getSigmoid(logOdds)
+		residual := sample.conversions - sample.journeys*probability
+		weight := sample.journeys * probability * (1 - probability)
+
+		gradient[intercept] += residual
+		hessian[intercept][intercept] += weight
+		for _, feature := range sample.features {
+			gradient[feature.index] += residual * feature.value
+			hessian[feature.index][intercept] += weight * feature.value
+			hessian[intercept][feature.index] += weight * feature.value
+			for _, other := range sample.features {
+				hessian[feature.index][other.index] += weight * feature.value * other.value
+			}
+		}
+	}
+	for index := 0; index < intercept; index++ {
+		gradient[index] -= regularization * parameters[index]
+		hessian[index][index] += regularization
+	}
+
+	return gradient, hessian
+}
+
+// getStandardErrors returns the square roots of the diagonal of the inverse of the given matrix, or NaN if it is
+// singular.
+func getStandardErrors(matrix [][]float64) []float64 {
+	standardErrors := make([]float64, len(matrix))
+	for index := range matrix {
+		unit := make([]float64, len(matrix))
+		unit[index] = 1
+		column, ok := solveLinearSystem(copyMatrix(matrix), unit)
+		if !ok || column[index] < 0 {
+			standardErrors[index] = math.NaN()
+			continue
+		}
+		standardErrors[index] = math.Sqrt(column[index])
+	}
+	return standardErrors
+}
+
+// copyMatrix returns a deep copy of the given matrix.
+func copyMatrix(matrix [][]float64) [][]float64 {
+	copied := make([][]float64, len(matrix))
+	for index, row := range matrix {
+		copied[index] = append([]float64(nil), row...)
+	}
+	return copied
+}
+
+// getSigmoid returns 1 / (1 + exp(-x)).
+func getSigmoid(x float64) float64 {
+	if x >= 0 {
+		return 1 / (1 + math.Exp(-x))
+	}
+	exp := math.Exp(x)
+	return exp / (1 + exp)
+}
+
+// getLogSigmoid returns the natural logarithm of getSigmoid(x) without underflowing for large negative x.
+func getLogSigmoid(x float64) float64 {
+	if x >= 0 {
+		return -math.Log1p(math.Exp(-x))
+	}
+	return x - math.Log1p(math.Exp(x))
+}
+
+// GetLogisticRegressionValue returns the logistic regression value of a given touchpoint over all provided
+// contributions, as described for GetLogisticRegressionValues.
+// A touchpoint that doesn't occur in any contribution has a value of zero.
+func GetLogisticRegressionValue(touchpoint Touchpoint, allContributions []Contribution, options LogisticRegressionOptions) big.Float {
+	return GetLogisticRegressionValues(allContributions, options)[touchpoint]
+}
+
+// GetLogisticRegressionValueChecked is like GetLogisticRegressionValue, but returns an error for empty or non-finite
+// input, for invalid counts, for invalid options, for touchpoints that don't occur in any contribution and for fits
+// that don't converge.
+func GetLogisticRegressionValueChecked(touchpoint Touchpoint, allContributions []Contribution, options LogisticRegressionOptions) (big.Float, error) {
+	if err := validateContributions(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateLogisticRegressionOptions(options); err != nil {
+		return big.Float{}, err
+	}
+	model, err := newConvergedLogisticRegression(allContributions, options)
+	if err != nil {
+		return big.Float{}, err
+	}
+	return GetCounterfactualValues(model, allContributions)[touchpoint], nil
+}
+
+// GetLogisticRegressionValues fits a logistic regression on the given contributions (see NewLogisticRegression) and
+// splits the value of every contribution among its touchpoints by the removal effects of GetCounterfactualValues.
+func GetLogisticRegressionValues(allContributions []Contribution, options LogisticRegressionOptions) AttributionResult {
+	return GetCounterfactualValues(NewLogisticRegression(allContributions, options), allContributions)
+}
+
+// GetLogisticRegressionValuesChecked is like GetLogisticRegressionValues, but returns an error for empty or non-finite
+// input, for invalid counts, for invalid options and for fits that don't converge, in particular if all or none of the
+// journeys converted.
+func GetLogisticRegressionValuesChecked(allContributions []Contribution, options LogisticRegressionOptions) (AttributionResult, error) {
+	if err := validateContributions(allContributions); err != nil {
+		return nil, err
+	}
+	if err := validateLogisticRegressionOptions(options); err != nil {
+		return nil, err
+	}
+	model, err := newConvergedLogisticRegression(allContributions, options)
+	if err != nil {
+		return nil, err
+	}
+	return GetCounterfactualValues(model, allContributions), nil
+}
+
+// newConvergedLogisticRegression is like NewLogisticRegression, but returns an error if the fit doesn't converge.
+// Without both converting and non-converting journeys, the intercept grows without bound, so such contributions are
+// rejected right away.
+func newConvergedLogisticRegression(allContributions []Contribution, options LogisticRegressionOptions) (LogisticRegression, error) {
+	var journeys, conversions int64
+	for _, contribution := range allContributions {
+		journeys += contribution.GetJourneys()
+		conversions += contribution.GetConversions()
+	}
+	if conversions == 0 || conversions == journeys {
+		return LogisticRegression{}, fmt.Errorf("%w: %d of %d journeys converted", ErrNotConverged, conversions, journeys)
+	}
+
+	model := NewLogisticRegression(allContributions, options)
+	if !model.Converged {
+		return LogisticRegression{}, fmt.Errorf("%w: no convergence after %d iterations", ErrNotConverged, model.Iterations)
+	}
+	return model, nil
+}
+
+// validateLogisticRegressionOptions checks that the given options are within their valid ranges.
+func validateLogisticRegressionOptions(options LogisticRegressionOptions) error {
+	if options.Regularization < 0 || math.IsNaN(options.Regularization) || math.IsInf(options.Regularization, 0) {
+		return fmt.Errorf("%w: regularization %g is negative or not finite", ErrInvalidParameter, options.Regularization)
+	}
+	if options.MaxIterations < 0 {
+		return fmt.Errorf("%w: maximal number of iterations %d is negative", ErrInvalidParameter, options.MaxIterations)
+	}
+	if options.Tolerance < 0 || math.IsNaN(options.Tolerance) {
+		return fmt.Errorf("%w: tolerance %g is negative", ErrInvalidParameter, options.Tolerance)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func logisticFixture() []Contribution {
+	return []Contribution{
+		Contribution{
+			Touchpoints: Touchpoints{Touchpoint{"search"}},
+			Value:       *new(big.Float).SetFloat64(300.),
+			Journeys:    100,
+			Conversions: 30,
+		},
+		Contribution{
+			Touchpoints: Touchpoints{Touchpoint{"display"}},
+			Value:       *new(big.Float).SetFloat64(100.),
+			Journeys:    100,
+			Conversions: 10,
+		},
+		Contribution{
+			Touchpoints: Touchpoints{Touchpoint{"display"}, Touchpoint{"search"}},
+			Value:       *new(big.Float).SetFloat64(950.),
+			Journeys:    200,
+			Conversions: 95,
+		},
+		Contribution{
+			Touchpoints: Touchpoints{},
+			Value:       *new(big.Float).SetFloat64(50.),
+			Journeys:    100,
+			Conversions: 5,
+		},
+	}
+}
+
+func ExampleNewLogisticRegression() {
+	model := NewLogisticRegression(logisticFixture(), LogisticRegressionOptions{})
+
+	fmt.Println(model.Converged)
+	fmt.Printf("%.2f\n", model.GetConversionProbability(Touchpoints{Touchpoint{"search"}}))
+	for _, diagnostic := range model.GetDiagnostics() {
+		fmt.Printf("%s %.3f %.2f\n", diagnostic.Touchpoint.Name, diagnostic.Coefficient, diagnostic.OddsRatio)
+	}
+	// Output:
+	// true
+	// 0.30
+	// display 0.747 2.11
+	// search 2.097 8.14
+}
+
+func ExampleGetLogisticRegressionValues() {
+	values := GetLogisticRegressionValues(logisticFixture(), LogisticRegressionOptions{})
+
+	for _, touchpoint := range values.GetTouchpoints() {
+		value := values[touchpoint]
+		fmt.Println(touchpoint.Name, value.Text('f', 2))
+	}
+	// Output:
+	// display 402.27
+	// search 947.73
+}
+
+func TestNewLogisticRegressionDiagnostics(t *testing.T) {
+	model := NewLogisticRegression(logisticFixture(), LogisticRegressionOptions{})
+
+	for _, diagnostic := range model.GetDiagnostics() {
+		if !(diagnostic.StandardError > 0) {
+			t.Errorf("%s: got standard error %f", diagnostic.Touchpoint.Name, diagnostic.StandardError)
+		}
+		if diagnostic.ZScore != diagnostic.Coefficient/diagnostic.StandardError {
+			t.Errorf("%s: got z-score %f", diagnostic.Touchpoint.Name, diagnostic.ZScore)
+		}
+	}
+	// the saturated model reproduces the observed conversion rates
+	want := 100*(0.3*math.Log(0.3)+0.7*math.Log(0.7)) + 100*(0.1*math.Log(0.1)+0.9*math.Log(0.9)) +
+		200*(0.475*math.Log(0.475)+0.525*math.Log(0.525)) + 100*(0.05*math.Log(0.05)+0.95*math.Log(0.95))
+	if math.Abs(model.LogLikelihood-want) > 1e-6 {
+		t.Errorf("got log-likelihood %f want %f", model.LogLikelihood, want)
+	}
+}
+
+func TestNewLogisticRegressionRegularization(t *testing.T) {
+	unregularized := NewLogisticRegression(logisticFixture(), LogisticRegressionOptions{})
+	regularized := NewLogisticRegression(logisticFixture(), LogisticRegressionOptions{Regularization: 100})
+
+	if !regularized.Converged {
+		t.Errorf("regularized fit didn't converge")
+	}
+	if !(math.Abs(regularized.Coefficients[1]) < math.Abs(unregularized.Coefficients[1])) {
+		t.Errorf("got coefficient %f want less than %f", regularized.Coefficients[1], unregularized.Coefficients[1])
+	}
+}
+
+func TestNewLogisticRegressionCounts(t *testing.T) {
+	contributions := logisticFixture()
+	contributions[0].Touchpoints = Touchpoints{Touchpoint{"search"}, Touchpoint{"search"}}
+	once := Touchpoints{Touchpoint{"search"}}
+	twice := Touchpoints{Touchpoint{"search"}, Touchpoint{"search"}}
+
+	presence := NewLogisticRegression(contributions, LogisticRegressionOptions{Regularization: 1})
+	if presence.GetConversionProbability(once) != presence.GetConversionProbability(twice) {
+		t.Errorf("presence features depend on repetition")
+	}
+	counts := NewLogisticRegression(contributions, LogisticRegressionOptions{Regularization: 1, Counts: true})
+	if !(counts.GetConversionProbability(twice) > counts.GetConversionProbability(once)) {
+		t.Errorf("count features don't depend on repetition")
+	}
+}
+
+func TestGetLogisticRegressionValuesChecked(t *testing.T) {
+	contributions := logisticFixture()
+
+	values, err := GetLogisticRegressionValuesChecked(contributions, LogisticRegressionOptions{Regularization: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := values.CheckEfficiency(getContributionSets(contributions[:3]), 1e-9); err != nil {
+		t.Error(err)
+	}
+	modelValues, err := LogisticRegressionModel{Options: LogisticRegressionOptions{Regularization: 1}}.Attribute(contributions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for touchpoint, value := range values {
+		modelValue := modelValues[touchpoint]
+		if modelValue.Cmp(&value) != 0 {
+			t.Errorf("%s: got %s want %s", touchpoint.Name, modelValue.String(), value.String())
+		}
+	}
+
+	// without non-converting journeys, the intercept diverges
+	converting := logisticFixture()
+	for index := range converting {
+		converting[index].Journeys = converting[index].Conversions
+	}
+	if _, err := GetLogisticRegressionValuesChecked(converting, LogisticRegressionOptions{Regularization: 1}); !errors.Is(err, ErrNotConverged) {
+		t.Errorf("got %v want ErrNotConverged", err)
+	}
+	if _, err := GetLogisticRegressionValuesChecked(contributions, LogisticRegressionOptions{MaxIterations: 1}); !errors.Is(err, ErrNotConverged) {
+		t.Errorf("got %v want ErrNotConverged", err)
+	}
+
+	if _, err := GetLogisticRegressionValuesChecked(nil, LogisticRegressionOptions{}); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("got %v want ErrEmptyInput", err)
+	}
+	for _, options := range []LogisticRegressionOptions{
+		LogisticRegressionOptions{Regularization: -1},
+		LogisticRegressionOptions{Regularization: math.Inf(1)},
+		LogisticRegressionOptions{MaxIterations: -1},
+		LogisticRegressionOptions{Tolerance: -1},
+	} {
+		if _, err := GetLogisticRegressionValuesChecked(contributions, options); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("%+v: got %v want ErrInvalidParameter", options, err)
+		}
+	}
+	if _, err := GetLogisticRegressionValueChecked(Touchpoint{"unknown"}, contributions, LogisticRegressionOptions{}); !errors.Is(err, ErrUnknownTouchpoint) {
+		t.Errorf("got %v want ErrUnknownTouchpoint", err)
+	}
+}
@@ -0,0 +1,487 @@
+package attribution
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// The names of the special states are reserved: the checked Markov functions reject contributions using them, since
+// such touchpoints would be merged with the special states.
+var (
+	// StartTouchpoint represents the state every path of a MarkovChain starts in.
+	StartTouchpoint = Touchpoint{"(start)"}
+	// ConversionTouchpoint represents the absorbing state of a MarkovChain reached by converting paths.
+	ConversionTouchpoint = Touchpoint{"(conversion)"}
+	// NullTouchpoint represents the absorbing state of a MarkovChain reached by paths that don't convert.
+	NullTouchpoint = Touchpoint{"(null)"}
+)
+
+// Indices of the special states of a MarkovChain.
+const (
+	startState = iota
+	conversionState
+	nullState
+	numberSpecialStates
+)
+
+// maxMarkovIterations and markovTolerance bound the iterations computing the absorption probabilities of a
+// MarkovChain: they stop once no probability changes by more than the tolerance.
+const (
+	maxMarkovIterations = 100000
+	markovTolerance     = 1e-14
+)
+
+// markovSmoothing is the pseudo count added to every transition when evaluating a MarkovChain on held-out paths.
+const markovSmoothing = 1.
+
+// A MarkovChain represents a Markov chain of a given order whose states are the most recent touchpoints of a path.
+// Every path begins in StartTouchpoint and ends in one of the absorbing states ConversionTouchpoint or NullTouchpoint.
+type MarkovChain struct {
+	Order int // number of most recent touchpoints a state consists of
+	// States lists the start, conversion and null state followed by all touchpoint states in sorted order.
+	// A touchpoint state holds the last Order touchpoints of a path, or fewer at the beginning of a path.
+	States      []Touchpoints
+	Transitions [][]float64 // Transitions[i][j] is the probability of moving from States[i] to States[j]
+}
+
+// NewMarkovChain estimates a first-order Markov chain from the given contributions.
+// Transitions are weighted by the number of journeys of each contribution. Its converting journeys end in
+// ConversionTouchpoint, the remaining ones in NullTouchpoint. Contributions without touchpoints are ignored.
+func NewMarkovChain(allContributions []Contribution) MarkovChain {
+	return NewHigherOrderMarkovChain(allContributions, 1)
+}
+
+// NewHigherOrderMarkovChain estimates a Markov chain of the given order from the given contributions.
+// Orders smaller than one are treated as one. See NewMarkovChain for details.
+func NewHigherOrderMarkovChain(allContributions []Contribution, order int) MarkovChain {
+	if order < 1 {
+		order = 1
+	}
+	chain := MarkovChain{
+		Order:  order,
+		States: []Touchpoints{Touchpoints{StartTouchpoint}, Touchpoints{ConversionTouchpoint}, Touchpoints{NullTouchpoint}},
+	}
+
+	seen := make(map[string]struct{})
+	var states []Touchpoints
+	for _, contribution := range allContributions {
+		forEachMarkovTransition(contribution, order, func(from Touchpoints, to Touchpoints, weight float64) {
+			key := getTouchpointsKey(to)
+			if _, found := seen[key]; !found && to[0] != ConversionTouchpoint && to[0] != NullTouchpoint {
+				seen[key] = struct{}{}
+				states = append(states, append(Touchpoints(nil), to...))
+			}
+		})
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return getTouchpointsKey(states[i]) < getTouchpointsKey(states[j])
+	})
+	chain.States = append(chain.States, states...)
+
+	indices := chain.getIndices()
+	counts := make([][]float64, len(chain.States))
+	for index := range counts {
+		counts[index] = make([]float64, len(chain.States))
+	}
+	for _, contribution := range allContributions {
+		forEachMarkovTransition(contribution, order, func(from Touchpoints, to Touchpoints, weight float64) {
+			counts[indices[getTouchpointsKey(from)]][indices[getTouchpointsKey(to)]] += weight
+		})
+	}
+	counts[conversionState][conversionState] = 1
+	counts[nullState][nullState] = 1
+
+	chain.Transitions = counts
+	for _, row := range chain.Transitions {
+		total := 0.
+		for _, count := range row {
+			total += count
+		}
+		if total == 0 {
+			// states without outgoing transitions can't lead to a conversion
+			row[nullState] = 1
+			continue
+		}
+		for index := range row {
+			row[index] /= total
+		}
+	}
+
+	return chain
+}
+
+// forEachMarkovTransition calls visit for every transition of the given contribution's path in a Markov chain of the
+// given order, weighted by the number of journeys taking it. Converting journeys end in ConversionTouchpoint, all other
+// journeys in NullTouchpoint. Contributions without touchpoints have no transitions.
+func forEachMarkovTransition(contribution Contribution, order int, visit func(from Touchpoints, to Touchpoints, weight float64)) {
+	if len(contribution.Touchpoints) == 0 {
+		return
+	}
+	journeys := float64(contribution.GetJourneys())
+	conversions := float64(contribution.GetConversions())
+
+	from := Touchpoints{StartTouchpoint}
+	for index := range contribution.Touchpoints {
+		first := index + 1 - order
+		if first < 0 {
+			first = 0
+		}
+		to := contribution.Touchpoints[first : index+1]
+		visit(from, to, journeys)
+		from = to
+	}
+	if conversions > 0 {
+		visit(from, Touchpoints{ConversionTouchpoint}, conversions)
+	}
+	if journeys > conversions {
+		visit(from, Touchpoints{NullTouchpoint}, journeys-conversions)
+	}
+}
+
+// getIndices returns the index of every state of the chain, keyed by getTouchpointsKey.
+func (chain MarkovChain) getIndices() map[string]int {
+	indices := make(map[string]int, len(chain.States))
+	for index, state := range chain.States {
+		indices[getTouchpointsKey(state)] = index
+	}
+	return indices
+}
+
+// GetTransitionProbability returns the probability of moving from one state of the chain to another.
+// Unknown states have a transition probability of zero.
+func (chain MarkovChain) GetTransitionProbability(from Touchpoints, to Touchpoints) float64 {
+	indices := chain.getIndices()
+	fromIndex, fromFound := indices[getTouchpointsKey(from)]
+	toIndex, toFound := indices[getTouchpointsKey(to)]
+	if !fromFound || !toFound {
+		return 0
+	}
+	return chain.Transitions[fromIndex][toIndex]
+}
+
+// GetConversionProbability returns the probability that a path starting in StartTouchpoint is absorbed in
+// ConversionTouchpoint.
+func (chain MarkovChain) GetConversionProbability() float64 {
+	return chain.getConversionProbability(make([]bool, len(chain.States)))
+}
+
+// GetRemovalEffect returns the relative drop in conversion probability if the given touchpoint is removed from the
+// chain, i.e. if every path reaching a state containing it is redirected to NullTouchpoint.
+func (chain MarkovChain) GetRemovalEffect(touchpoint Touchpoint) float64 {
+	removed := make([]bool, len(chain.States))
+	found := false
+	for index := numberSpecialStates; index < len(chain.States); index++ {
+		if _, contained := findTouchpoint(touchpoint, chain.States[index]); contained {
+			removed[index] = true
+			found = true
+		}
+	}
+	if !found {
+		return 0
+	}
+	conversionProbability := chain.GetConversionProbability()
+	if conversionProbability == 0 {
+		return 0
+	}
+	return 1 - chain.getConversionProbability(removed)/conversionProbability
+}
+
+// getConversionProbability returns the absorption probability in ConversionTouchpoint when starting in
+// StartTouchpoint, treating all removed states as leading to NullTouchpoint.
+// The absorption probabilities x satisfy x = Q x + r for the transitions Q between transient states and the
+// transitions r into ConversionTouchpoint. Since every path is eventually absorbed, Gauss-Seidel iterations starting at
+// zero increase monotonically towards them, taking time linear in the number of non-zero transitions per iteration
+// rather than cubic in the number of states as a dense solution would.
+func (chain MarkovChain) getConversionProbability(removed []bool) float64 {
+	size := len(chain.States)
+	// successors[i] lists the transient states reachable from the transient state i
+	successors := make([][]markovTransition, size)
+	conversionProbabilities := make([]float64, size)
+	for i := range chain.Transitions {
+		if i == conversionState || i == nullState || removed[i] {
+			continue
+		}
+		for j, probability := range chain.Transitions[i] {
+			if j == conversionState || j == nullState || removed[j] || probability == 0 {
+				continue
+			}
+			successors[i] = append(successors[i], markovTransition{to: j, probability: probability})
+		}
+		conversionProbabilities[i] = chain.Transitions[i][conversionState]
+	}
+
+	solution := make([]float64, size)
+	for iteration := 0; iteration < maxMarkovIterations; iteration++ {
+		maxChange := 0.
+		for i, transitions := range successors {
+			probability := conversionProbabilities[i]
+			for _, transition := range transitions {
+				probability += transition.probability * solution[transition.to]
+			}
+			maxChange = math.Max(maxChange, probability-solution[i])
+			solution[i] = probability
+		}
+		if maxChange <= markovTolerance {
+			break
+		}
+	}
+	return solution[startState]
+}
+
+// A markovTransition represents a transition with non-zero probability into the state with the given index.
+type markovTransition struct {
+	to          int
+	probability float64
+}
+
+// solveLinearSystem solves matrix * x = vector using Gaussian elimination with partial pivoting.
+// Both arguments are modified in place. If the matrix is singular, false is returned in the second coordinate.
+func solveLinearSystem(matrix [][]float64, vector []float64) ([]float64, bool) {
+	size := len(vector)
+
+	for column := 0; column < size; column++ {
+		pivot := column
+		for row := column + 1; row < size; row++ {
+			if math.Abs(matrix[row][column]) > math.Abs(matrix[pivot][column]) {
+				pivot = row
+			}
+		}
+		if math.Abs(matrix[pivot][column]) < 1e-12 {
+			return nil, false
+		}
+		matrix[column], matrix[pivot] = matrix[pivot], matrix[column]
+		vector[column], vector[pivot] = vector[pivot], vector[column]
+
+		for row := column + 1; row < size; row++ {
+			factor := matrix[row][column] / matrix[column][column]
+			if factor == 0 {
+				continue
+			}
+			for k := column; k < size; k++ {
+				matrix[row][k] -= factor * matrix[column][k]
+			}
+			vector[row] -= factor * vector[column]
+		}
+	}
+
+	solution := make([]float64, size)
+	for row := size - 1; row >= 0; row-- {
+		sum := vector[row]
+		for k := row + 1; k < size; k++ {
+			sum -= matrix[row][k] * solution[k]
+		}
+		solution[row] = sum / matrix[row][row]
+	}
+
+	return solution, true
+}
+
+// GetMarkovValue returns the Markov chain value of a given touchpoint over all provided contributions.
+// The value is the touchpoint's removal effect in a first-order Markov chain, scaled such that the values of all
+// touchpoints add up to the total value of all contributions with at least one touchpoint.
+func GetMarkovValue(touchpoint Touchpoint, allContributions []Contribution) big.Float {
+	return GetMarkovValues(allContributions)[touchpoint]
+}
+
+// GetMarkovValueChecked is like GetMarkovValue, but returns an error for empty or non-finite input and for
+// touchpoints that don't occur in any contribution.
+func GetMarkovValueChecked(touchpoint Touchpoint, allContributions []Contribution) (big.Float, error) {
+	return GetHigherOrderMarkovValueChecked(touchpoint, allContributions, 1)
+}
+
+// GetMarkovValues returns the Markov chain values of all touchpoints encountered in the provided contributions.
+// See GetMarkovValue for details.
+func GetMarkovValues(allContributions []Contribution) AttributionResult {
+	return GetHigherOrderMarkovValues(allContributions, 1)
+}
+
+// GetMarkovValuesChecked is like GetMarkovValues, but returns an error for empty or non-finite input and for
+// touchpoints named like a special state.
+func GetMarkovValuesChecked(allContributions []Contribution) (AttributionResult, error) {
+	return GetHigherOrderMarkovValuesChecked(allContributions, 1)
+}
+
+// GetHigherOrderMarkovValue is like GetMarkovValue, but uses a Markov chain of the given order.
+func GetHigherOrderMarkovValue(touchpoint Touchpoint, allContributions []Contribution, order int) big.Float {
+	return GetHigherOrderMarkovValues(allContributions, order)[touchpoint]
+}
+
+// GetHigherOrderMarkovValueChecked is like GetHigherOrderMarkovValue, but returns an error for empty or non-finite
+// input, for touchpoints that don't occur in any contribution or are named like a special state and for orders smaller
+// than one.
+func GetHigherOrderMarkovValueChecked(touchpoint Touchpoint, allContributions []Contribution, order int) (big.Float, error) {
+	if err := validateContributions(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateMarkovTouchpoints(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateMarkovOrder(order); err != nil {
+		return big.Float{}, err
+	}
+	return GetHigherOrderMarkovValue(touchpoint, allContributions, order), nil
+}
+
+// GetHigherOrderMarkovValues is like GetMarkovValues, but uses a Markov chain of the given order.
+func GetHigherOrderMarkovValues(allContributions []Contribution, order int) AttributionResult {
+	chain := NewHigherOrderMarkovChain(allContributions, order)
+
+	seen := make(map[Touchpoint]struct{})
+	var touchpoints Touchpoints
+	for _, state := range chain.States[numberSpecialStates:] {
+		for _, touchpoint := range state {
+			if _, found := seen[touchpoint]; !found {
+				seen[touchpoint] = struct{}{}
+				touchpoints = append(touchpoints, touchpoint)
+			}
+		}
+	}
+
+	return getRemovalEffectValues(chain, touchpoints, allContributions)
+}
+
+// GetHigherOrderMarkovValuesChecked is like GetHigherOrderMarkovValues, but returns an error for empty or non-finite
+// input, for touchpoints named like a special state and for orders smaller than one.
+func GetHigherOrderMarkovValuesChecked(allContributions []Contribution, order int) (AttributionResult, error) {
+	if err := validateContributions(allContributions); err != nil {
+		return nil, err
+	}
+	if err := validateMarkovTouchpoints(allContributions); err != nil {
+		return nil, err
+	}
+	if err := validateMarkovOrder(order); err != nil {
+		return nil, err
+	}
+	return GetHigherOrderMarkovValues(allContributions, order), nil
+}
+
+// validateMarkovOrder checks that the given order of a Markov chain is positive.
+func validateMarkovOrder(order int) error {
+	if order < 1 {
+		return fmt.Errorf("%w: Markov chain order %d is smaller than one", ErrInvalidParameter, order)
+	}
+	return nil
+}
+
+// validateMarkovTouchpoints checks that no touchpoint of the given contributions is named like one of the special
+// states of a MarkovChain.
+func validateMarkovTouchpoints(allContributions []Contribution) error {
+	for index, contribution := range allContributions {
+		for _, touchpoint := range contribution.Touchpoints {
+			if touchpoint == StartTouchpoint || touchpoint == ConversionTouchpoint || touchpoint == NullTouchpoint {
+				return fmt.Errorf("%w: touchpoint %s of contribution %d is reserved for a Markov chain state", ErrInvalidParameter, touchpoint.Name, index)
+			}
+		}
+	}
+	return nil
+}
+
+// getRemovalEffectValues distributes the total value of all contributions with at least one touchpoint among the given
+// touchpoints proportionally to their removal effects in the given chain.
+func getRemovalEffectValues(chain MarkovChain, touchpoints Touchpoints, allContributions []Contribution) AttributionResult {
+	totalValue := new(big.Float)
+	for _, contribution := range allContributions {
+		if len(contribution.Touchpoints) > 0 {
+			totalValue.Add(totalValue, &contribution.Value)
+		}
+	}
+
+	removalEffects := make([]float64, len(touchpoints))
+	totalRemovalEffect := 0.
+	for index, touchpoint := range touchpoints {
+		removalEffects[index] = chain.GetRemovalEffect(touchpoint)
+		totalRemovalEffect += removalEffects[index]
+	}
+
+	markovValues := make(AttributionResult, len(touchpoints))
+	for index, touchpoint := range touchpoints {
+		markovValue := new(big.Float)
+		if totalRemovalEffect > 0 {
+			share := new(big.Float).SetFloat64(removalEffects[index] / totalRemovalEffect)
+			markovValue.Mul(totalValue, share)
+		}
+		markovValues[touchpoint] = *markovValue
+	}
+
+	return markovValues
+}
+
+// A MarkovOrderScore represents how well a Markov chain of a given order predicts held-out paths.
+type MarkovOrderScore struct {
+	Order         int
+	LogLikelihood float64 // natural logarithm of the likelihood of all held-out paths
+	Transitions   int     // number of held-out transitions the log-likelihood is summed over, weighted by journeys
+}
+
+// CompareMarkovOrders fits Markov chains of the given orders on the training contributions and returns their
+// log-likelihood on the held-out contributions, in the order the orders were given.
+// To avoid zero probabilities for transitions that don't occur in the training data, each possible next touchpoint
+// receives a pseudo count of one (Laplace smoothing).
+func CompareMarkovOrders(training []Contribution, heldOut []Contribution, orders []int) []MarkovOrderScore {
+	// the possible next touchpoints of every state are all known touchpoints and the absorbing states
+	vocabulary := make(map[Touchpoint]struct{})
+	vocabulary[ConversionTouchpoint] = struct{}{}
+	vocabulary[NullTouchpoint] = struct{}{}
+	for _, contributions := range [][]Contribution{training, heldOut} {
+		for _, contribution := range contributions {
+			for _, touchpoint := range contribution.Touchpoints {
+				vocabulary[touchpoint] = struct{}{}
+			}
+		}
+	}
+	vocabularySize := float64(len(vocabulary))
+
+	scores := make([]MarkovOrderScore, len(orders))
+	for index, order := range orders {
+		if order < 1 {
+			order = 1
+		}
+		counts := make(map[string]map[Touchpoint]float64)
+		totals := make(map[string]float64)
+		for _, contribution := range training {
+			forEachMarkovTransition(contribution, order, func(from Touchpoints, to Touchpoints, weight float64) {
+				key := getTouchpointsKey(from)
+				if _, found := counts[key]; !found {
+					counts[key] = make(map[Touchpoint]float64)
+				}
+				counts[key][to[len(to)-1]] += weight
+				totals[key] += weight
+			})
+		}
+
+		score := MarkovOrderScore{Order: order}
+		for _, contribution := range heldOut {
+			forEachMarkovTransition(contribution, order, func(from Touchpoints, to Touchpoints, weight float64) {
+				key := getTouchpointsKey(from)
+				probability := (counts[key][to[len(to)-1]] + markovSmoothing) /
+					(totals[key] + markovSmoothing*vocabularySize)
+				score.LogLikelihood += weight * math.Log(probability)
+				score.Transitions += int(weight)
+			})
+		}
+		scores[index] = score
+	}
+
+	return scores
+}
+
+// SelectMarkovOrder returns the order among the given orders whose Markov chain achieves the highest log-likelihood
+// on the held-out contributions. See CompareMarkovOrders for details.
+// Ties are resolved in favor of the smaller order. If no orders are given, one is returned.
+func SelectMarkovOrder(training []Contribution, heldOut []Contribution, orders []int) int {
+	bestOrder := 1
+	bestLogLikelihood := math.Inf(-1)
+	for _, score := range CompareMarkovOrders(training, heldOut, orders) {
+		if score.LogLikelihood > bestLogLikelihood ||
+			(score.LogLikelihood == bestLogLikelihood && score.Order < bestOrder) {
+			bestOrder = score.Order
+			bestLogLikelihood = score.LogLikelihood
+		}
+	}
+	return bestOrder
+}
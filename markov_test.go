@@ -0,0 +1,331 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func ExampleGetMarkovValues() {
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Touchpoint 1"},
+				Touchpoint{"Touchpoint 2"},
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Touchpoint 1"},
+			},
+			Value: *new(big.Float).SetFloat64(200.),
+		},
+	}
+	markovValues := GetMarkovValues(contributions)
+
+	for _, touchpoint := range []Touchpoint{Touchpoint{"Touchpoint 1"}, Touchpoint{"Touchpoint 2"}} {
+		markovValue := markovValues[touchpoint]
+		fmt.Println(touchpoint.Name, markovValue.String())
+	}
+	// Output:
+	// Touchpoint 1 200
+	// Touchpoint 2 100
+}
+
+func TestNewMarkovChain(t *testing.T) {
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: []Touchpoint{Touchpoint{"a"}, Touchpoint{"b"}},
+			Value:       *new(big.Float).SetFloat64(100.),
+		},
+		Contribution{
+			Touchpoints: []Touchpoint{Touchpoint{"a"}},
+			Value:       *new(big.Float).SetFloat64(200.),
+		},
+		Contribution{
+			Touchpoints: []Touchpoint{Touchpoint{"b"}, Touchpoint{"b"}},
+			Value:       *new(big.Float).SetFloat64(200.),
+		},
+	}
+	chain := NewMarkovChain(contributions)
+
+	if len(chain.States) != 5 {
+		t.Fatalf("got %d states want 5", len(chain.States))
+	}
+	transitions := []struct {
+		from Touchpoint
+		to   Touchpoint
+		want float64
+	}{
+		{StartTouchpoint, Touchpoint{"a"}, 2. / 3.},
+		{StartTouchpoint, Touchpoint{"b"}, 1. / 3.},
+		{Touchpoint{"a"}, Touchpoint{"b"}, 0.5},
+		{Touchpoint{"a"}, ConversionTouchpoint, 0.5},
+		{Touchpoint{"b"}, Touchpoint{"b"}, 1. / 3.},
+		{Touchpoint{"b"}, ConversionTouchpoint, 2. / 3.},
+		{ConversionTouchpoint, ConversionTouchpoint, 1},
+		{NullTouchpoint, NullTouchpoint, 1},
+		{Touchpoint{"b"}, Touchpoint{"a"}, 0},
+		{Touchpoint{"unknown"}, Touchpoint{"a"}, 0},
+	}
+	for _, transition := range transitions {
+		got := chain.GetTransitionProbability(Touchpoints{transition.from}, Touchpoints{transition.to})
+		if math.Abs(got-transition.want) > 1e-12 {
+			t.Errorf("%s -> %s: got %f want %f", transition.from, transition.to, got, transition.want)
+		}
+	}
+
+	if got := chain.GetConversionProbability(); math.Abs(got-1) > 1e-12 {
+		t.Errorf("got conversion probability %f want 1", got)
+	}
+	if got := chain.GetRemovalEffect(Touchpoint{"a"}); math.Abs(got-2./3.) > 1e-12 {
+		t.Errorf("got removal effect %f want %f", got, 2./3.)
+	}
+	if got := chain.GetRemovalEffect(Touchpoint{"b"}); math.Abs(got-2./3.) > 1e-12 {
+		t.Errorf("got removal effect %f want %f", got, 2./3.)
+	}
+}
+
+func TestGetMarkovValues(t *testing.T) {
+	contributions := contributionFixture()
+	markovValues := GetMarkovValues(contributions)
+
+	totalValue := new(big.Float)
+	for _, contribution := range contributions {
+		if len(contribution.Touchpoints) > 0 {
+			totalValue.Add(totalValue, &contribution.Value)
+		}
+	}
+	sum := new(big.Float)
+	for touchpoint := range markovValues {
+		markovValue := GetMarkovValue(touchpoint, contributions)
+		sum.Add(sum, &markovValue)
+	}
+
+	got, _ := sum.Float64()
+	want, _ := totalValue.Float64()
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("got %f want %f", got, want)
+	}
+}
+
+func TestGetMarkovValueChecked(t *testing.T) {
+	contributions := contributionFixture()
+
+	if _, err := GetMarkovValueChecked(touchpointFixture()[2], contributions); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := GetMarkovValueChecked(touchpointFixture()[9], contributions); !errors.Is(err, ErrUnknownTouchpoint) {
+		t.Errorf("got %v want %v", err, ErrUnknownTouchpoint)
+	}
+	if _, err := GetMarkovValuesChecked(nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("got %v want %v", err, ErrEmptyInput)
+	}
+}
+
+func TestNewHigherOrderMarkovChain(t *testing.T) {
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: []Touchpoint{Touchpoint{"a"}, Touchpoint{"b"}, Touchpoint{"c"}},
+			Value:       *new(big.Float).SetFloat64(100.),
+		},
+		Contribution{
+			Touchpoints: []Touchpoint{Touchpoint{"b"}, Touchpoint{"c"}},
+			Value:       *new(big.Float).SetFloat64(100.),
+		},
+	}
+	chain := NewHigherOrderMarkovChain(contributions, 2)
+
+	// start, conversion, null, [a], [a b], [b], [b c]
+	if len(chain.States) != 7 {
+		t.Fatalf("got %d states %v want 7", len(chain.States), chain.States)
+	}
+	transitions := []struct {
+		from Touchpoints
+		to   Touchpoints
+		want float64
+	}{
+		{Touchpoints{StartTouchpoint}, Touchpoints{Touchpoint{"a"}}, 0.5},
+		{Touchpoints{Touchpoint{"a"}}, Touchpoints{Touchpoint{"a"}, Touchpoint{"b"}}, 1},
+		{Touchpoints{Touchpoint{"a"}, Touchpoint{"b"}}, Touchpoints{Touchpoint{"b"}, Touchpoint{"c"}}, 1},
+		{Touchpoints{Touchpoint{"b"}}, Touchpoints{Touchpoint{"b"}, Touchpoint{"c"}}, 1},
+		{Touchpoints{Touchpoint{"b"}, Touchpoint{"c"}}, Touchpoints{ConversionTouchpoint}, 1},
+	}
+	for _, transition := range transitions {
+		got := chain.GetTransitionProbability(transition.from, transition.to)
+		if math.Abs(got-transition.want) > 1e-12 {
+			t.Errorf("%s -> %s: got %f want %f", transition.from, transition.to, got, transition.want)
+		}
+	}
+
+	// every path passes through c, but only half of them through a
+	if got := chain.GetRemovalEffect(Touchpoint{"c"}); math.Abs(got-1) > 1e-12 {
+		t.Errorf("got removal effect %f want 1", got)
+	}
+	if got := chain.GetRemovalEffect(Touchpoint{"a"}); math.Abs(got-0.5) > 1e-12 {
+		t.Errorf("got removal effect %f want 0.5", got)
+	}
+}
+
+func TestMarkovChainConversionProbability(t *testing.T) {
+	contributions := contributionFixture()
+	contributions[0].Journeys = 4
+	contributions[1].Journeys = 3
+
+	for order := 1; order <= 3; order++ {
+		chain := NewHigherOrderMarkovChain(contributions, order)
+		// solve the absorption equations densely instead of iteratively
+		size := len(chain.States)
+		matrix := make([][]float64, size)
+		vector := make([]float64, size)
+		for i := range matrix {
+			matrix[i] = make([]float64, size)
+			matrix[i][i] = 1
+			if i == conversionState {
+				vector[i] = 1
+			}
+			if i == conversionState || i == nullState {
+				continue
+			}
+			for j, probability := range chain.Transitions[i] {
+				if j != conversionState && j != nullState {
+					matrix[i][j] -= probability
+				}
+			}
+			vector[i] = chain.Transitions[i][conversionState]
+		}
+		solution, ok := solveLinearSystem(matrix, vector)
+		if !ok {
+			t.Fatalf("order %d: singular absorption equations", order)
+		}
+
+		if got := chain.GetConversionProbability(); math.Abs(got-solution[startState]) > 1e-12 {
+			t.Errorf("order %d: got %f want %f", order, got, solution[startState])
+		}
+	}
+}
+
+func TestGetHigherOrderMarkovValuesManyStates(t *testing.T) {
+	// all paths of three out of 15 channels make for thousands of third-order states
+	var contributions []Contribution
+	for first := 0; first < 15; first++ {
+		for second := 0; second < 15; second++ {
+			for third := 0; third < 15; third += 2 {
+				contributions = append(contributions, Contribution{
+					Touchpoints: Touchpoints{
+						Touchpoint{fmt.Sprintf("channel %d", first)},
+						Touchpoint{fmt.Sprintf("channel %d", second)},
+						Touchpoint{fmt.Sprintf("channel %d", third)},
+					},
+					Value:       *big.NewFloat(1),
+					Journeys:    int64(1 + (first+second+third)%3),
+					Conversions: 1,
+				})
+			}
+		}
+	}
+
+	values, err := GetHigherOrderMarkovValuesChecked(contributions, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := values.CheckEfficiency(getContributionSets(contributions), 1e-6); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetHigherOrderMarkovValuesChecked(t *testing.T) {
+	contributions := contributionFixture()
+
+	for order := 1; order <= 3; order++ {
+		markovValues, err := GetHigherOrderMarkovValuesChecked(contributions, order)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sum := new(big.Float)
+		for touchpoint := range markovValues {
+			markovValue := markovValues[touchpoint]
+			sum.Add(sum, &markovValue)
+		}
+		if got, _ := sum.Float64(); math.Abs(got-5000) > 1e-6 {
+			t.Errorf("order %d: got %f want %f", order, got, 5000.)
+		}
+	}
+
+	if _, err := GetHigherOrderMarkovValuesChecked(contributions, 0); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("got %v want %v", err, ErrInvalidParameter)
+	}
+}
+
+func TestGetMarkovValuesCheckedReservedNames(t *testing.T) {
+	for _, reserved := range []Touchpoint{StartTouchpoint, ConversionTouchpoint, NullTouchpoint} {
+		contributions := contributionFixture()
+		contributions[0].Touchpoints = append(contributions[0].Touchpoints, Touchpoint{reserved.Name})
+
+		if _, err := GetMarkovValuesChecked(contributions); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("%s: got %v want %v", reserved.Name, err, ErrInvalidParameter)
+		}
+		if _, err := GetMarkovValueChecked(touchpointFixture()[1], contributions); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("%s: got %v want %v", reserved.Name, err, ErrInvalidParameter)
+		}
+	}
+}
+
+func ExampleSelectMarkovOrder() {
+	path := func(names ...string) Contribution {
+		var touchpoints Touchpoints
+		for _, name := range names {
+			touchpoints = append(touchpoints, Touchpoint{name})
+		}
+		return Contribution{Touchpoints: touchpoints, Value: *new(big.Float).SetFloat64(1.)}
+	}
+	// the touchpoint following search depends on the touchpoint preceding it
+	var training, heldOut []Contribution
+	for i := 0; i < 10; i++ {
+		training = append(training, path("display", "search", "email"), path("social", "search", "display"))
+		heldOut = append(heldOut, path("display", "search", "email"), path("social", "search", "display"))
+	}
+
+	for _, score := range CompareMarkovOrders(training, heldOut, []int{1, 2}) {
+		fmt.Printf("%d %.2f\n", score.Order, score.LogLikelihood)
+	}
+	fmt.Println(SelectMarkovOrder(training, heldOut, []int{1, 2, 3}))
+	// Output:
+	// 1 -59.11
+	// 2 -39.69
+	// 2
+}
+
+func TestNewMarkovChainNonConverting(t *testing.T) {
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: []Touchpoint{Touchpoint{"a"}, Touchpoint{"b"}},
+			Value:       *new(big.Float).SetFloat64(100.),
+			Journeys:    4,
+			Conversions: 1,
+		},
+		Contribution{
+			Touchpoints: []Touchpoint{Touchpoint{"b"}},
+			Journeys:    4,
+			Conversions: 0,
+		},
+	}
+	chain := NewMarkovChain(contributions)
+
+	if got := chain.GetTransitionProbability(Touchpoints{Touchpoint{"b"}}, Touchpoints{NullTouchpoint}); math.Abs(got-7./8.) > 1e-12 {
+		t.Errorf("got %f want %f", got, 7./8.)
+	}
+	if got := chain.GetConversionProbability(); math.Abs(got-1./8.) > 1e-12 {
+		t.Errorf("got conversion probability %f want %f", got, 1./8.)
+	}
+	// without a, only half of the journeys reach b
+	if got := chain.GetRemovalEffect(Touchpoint{"a"}); math.Abs(got-0.5) > 1e-12 {
+		t.Errorf("got removal effect %f want 0.5", got)
+	}
+	if got := chain.GetRemovalEffect(Touchpoint{"b"}); math.Abs(got-1) > 1e-12 {
+		t.Errorf("got removal effect %f want 1", got)
+	}
+}
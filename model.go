@@ -0,0 +1,343 @@
+package attribution
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A Model attributes the value of contributions to all touchpoints encountered in them.
+type Model interface {
+	// Name returns the name the model is registered under.
+	Name() string
+	// Attribute returns the value attributed to every touchpoint encountered in the given contributions.
+	Attribute(allContributions []Contribution) (AttributionResult, error)
+}
+
+// FirstTouchpointModel attributes value as GetFirstTouchpointValue does.
+type FirstTouchpointModel struct{}
+
+// Name returns "first_touchpoint".
+func (model FirstTouchpointModel) Name() string {
+	return "first_touchpoint"
+}
+
+// Attribute returns the first touchpoint value of every touchpoint.
+func (model FirstTouchpointModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	return attributeEach(allContributions, func(touchpoint Touchpoint) big.Float {
+		return GetFirstTouchpointValue(touchpoint, allContributions)
+	})
+}
+
+// LastTouchpointModel attributes value as GetLastTouchpointValue does.
+type LastTouchpointModel struct{}
+
+// Name returns "last_touchpoint".
+func (model LastTouchpointModel) Name() string {
+	return "last_touchpoint"
+}
+
+// Attribute returns the last touchpoint value of every touchpoint.
+func (model LastTouchpointModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	return attributeEach(allContributions, func(touchpoint Touchpoint) big.Float {
+		return GetLastTouchpointValue(touchpoint, allContributions)
+	})
+}
+
+// LinearModel attributes value as GetLinearValue does.
+type LinearModel struct{}
+
+// Name returns "linear".
+func (model LinearModel) Name() string {
+	return "linear"
+}
+
+// Attribute returns the linear value (ignoring repetition) of every touchpoint.
+func (model LinearModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	contributionSets := getContributionSets(allContributions)
+	return attributeEach(allContributions, func(touchpoint Touchpoint) big.Float {
+		return GetLinearValue(touchpoint, contributionSets)
+	})
+}
+
+// RepeatedLinearModel attributes value as GetRepeatedLinearValue does.
+type RepeatedLinearModel struct{}
+
+// Name returns "repeated_linear".
+func (model RepeatedLinearModel) Name() string {
+	return "repeated_linear"
+}
+
+// Attribute returns the linear value (with repetition) of every touchpoint.
+func (model RepeatedLinearModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	return attributeEach(allContributions, func(touchpoint Touchpoint) big.Float {
+		return GetRepeatedLinearValue(touchpoint, allContributions)
+	})
+}
+
+// TimeDecayModel attributes value as GetTimeDecayValue does.
+type TimeDecayModel struct {
+	HalfLife time.Duration
+}
+
+// Name returns "time_decay".
+func (model TimeDecayModel) Name() string {
+	return "time_decay"
+}
+
+// Attribute returns the time-decay value of every touchpoint.
+// Contributions without timestamps are distributed as in RepeatedLinearModel.
+func (model TimeDecayModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	if model.HalfLife <= 0 {
+		return nil, fmt.Errorf("%w: half-life %s is not positive", ErrInvalidParameter, model.HalfLife)
+	}
+	return attributeEach(allContributions, func(touchpoint Touchpoint) big.Float {
+		return GetTimeDecayValue(touchpoint, allContributions, model.HalfLife)
+	})
+}
+
+// PositionBasedModel attributes value as GetPositionBasedValue does.
+type PositionBasedModel struct {
+	ModelName string // name the model is registered under
+	Weights   PositionWeights
+}
+
+// Name returns the model's ModelName.
+func (model PositionBasedModel) Name() string {
+	return model.ModelName
+}
+
+// Attribute returns the position-based value of every touchpoint.
+func (model PositionBasedModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	if model.Weights == nil {
+		return nil, fmt.Errorf("%w: missing position weights", ErrInvalidParameter)
+	}
+	return attributeEach(allContributions, func(touchpoint Touchpoint) big.Float {
+		return GetPositionBasedValue(touchpoint, allContributions, model.Weights)
+	})
+}
+
+// ShapleyModel attributes value as GetShapleyValues does, ignoring the order of touchpoints.
+type ShapleyModel struct{}
+
+// Name returns "shapley".
+func (model ShapleyModel) Name() string {
+	return "shapley"
+}
+
+// Attribute returns the Shapley value of every touchpoint.
+func (model ShapleyModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	return GetShapleyValuesChecked(getContributionSets(allContributions))
+}
+
+// CharacteristicShapleyModel attributes value as GetShapleyValuesWith does, using the characteristic function returned by
+// Function for the contributions. The values are measured in the unit of the characteristic function.
+type CharacteristicShapleyModel struct {
+	ModelName string // name the model is registered under
+	Function  func(allContributions []ContributionSet) CharacteristicFunction
+}
+
+// Name returns the model's ModelName.
+func (model CharacteristicShapleyModel) Name() string {
+	return model.ModelName
+}
+
+// Attribute returns the Shapley value of every touchpoint in the game defined by the model's characteristic function.
+func (model CharacteristicShapleyModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	if model.Function == nil {
+		return nil, fmt.Errorf("%w: missing characteristic function", ErrInvalidParameter)
+	}
+	if err := validateContributions(allContributions); err != nil {
+		return nil, err
+	}
+	return GetShapleyValuesWith(model.Function(getContributionSets(allContributions))), nil
+}
+
+// OwenModel attributes value as GetOwenValues does, ignoring the order of touchpoints.
+type OwenModel struct {
+	Groups []Touchpoints // partition of the touchpoints; if nil, touchpoints are grouped by their top hierarchy level
+}
+
+// Name returns "owen".
+func (model OwenModel) Name() string {
+	return "owen"
+}
+
+// Attribute returns the Owen value of every touchpoint.
+func (model OwenModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	contributionSets := getContributionSets(allContributions)
+	groups := model.Groups
+	if groups == nil {
+		groups = GroupByLevel(GetAllTouchpoints(contributionSets), 1)
+	}
+	return GetOwenValuesChecked(contributionSets, groups)
+}
+
+// BanzhafModel attributes value as GetNormalizedBanzhafValues does, ignoring the order of touchpoints.
+type BanzhafModel struct{}
+
+// Name returns "banzhaf".
+func (model BanzhafModel) Name() string {
+	return "banzhaf"
+}
+
+// Attribute returns the normalized Banzhaf value of every touchpoint.
+func (model BanzhafModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	return GetNormalizedBanzhafValuesChecked(getContributionSets(allContributions))
+}
+
+// CausalModel attributes value as GetCausalValues does, ignoring the order of touchpoints.
+type CausalModel struct{}
+
+// Name returns "causal".
+func (model CausalModel) Name() string {
+	return "causal"
+}
+
+// Attribute returns the causally motivated value of every touchpoint.
+func (model CausalModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	return GetCausalValuesChecked(getContributionSets(allContributions))
+}
+
+// LogisticRegressionModel attributes value as GetLogisticRegressionValues does.
+type LogisticRegressionModel struct {
+	Options LogisticRegressionOptions
+}
+
+// Name returns "logistic_regression".
+func (model LogisticRegressionModel) Name() string {
+	return "logistic_regression"
+}
+
+// Attribute returns the logistic regression value of every touchpoint.
+func (model LogisticRegressionModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	return GetLogisticRegressionValuesChecked(allContributions, model.Options)
+}
+
+// CounterfactualModel attributes value as GetCounterfactualValues or, if Shapley is set, as
+// GetCounterfactualShapleyValues does, based on the predictions of the given conversion model.
+type CounterfactualModel struct {
+	ModelName       string // name the model is registered under
+	ConversionModel ConversionModel
+	Shapley         bool // use Shapley values of predicted probabilities instead of removal effects
+}
+
+// Name returns the model's ModelName.
+func (model CounterfactualModel) Name() string {
+	return model.ModelName
+}
+
+// Attribute returns the counterfactual value of every touchpoint.
+func (model CounterfactualModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	if model.Shapley {
+		return GetCounterfactualShapleyValuesChecked(model.ConversionModel, allContributions)
+	}
+	return GetCounterfactualValuesChecked(model.ConversionModel, allContributions)
+}
+
+// MarkovModel attributes value as GetHigherOrderMarkovValues does.
+type MarkovModel struct {
+	Order int
+}
+
+// Name returns "markov".
+func (model MarkovModel) Name() string {
+	return "markov"
+}
+
+// Attribute returns the Markov chain value of every touchpoint.
+func (model MarkovModel) Attribute(allContributions []Contribution) (AttributionResult, error) {
+	return GetHigherOrderMarkovValuesChecked(allContributions, model.Order)
+}
+
+// attributeEach validates the given contributions and evaluates getValue for every touchpoint encountered in them.
+func attributeEach(allContributions []Contribution, getValue func(touchpoint Touchpoint) big.Float) (AttributionResult, error) {
+	if err := validateContributions(allContributions); err != nil {
+		return nil, err
+	}
+
+	touchpoints := GetAllTouchpoints(getContributionSets(allContributions))
+	values := make(AttributionResult, len(touchpoints))
+	for _, touchpoint := range touchpoints {
+		values[touchpoint] = getValue(touchpoint)
+	}
+
+	return values, nil
+}
+
+// getContributionSets transforms a list of Contribution objects into a list of corresponding ContributionSet objects.
+func getContributionSets(allContributions []Contribution) []ContributionSet {
+	contributionSets := make([]ContributionSet, len(allContributions))
+	for index, contribution := range allContributions {
+		contributionSets[index] = contribution.Set()
+	}
+	return contributionSets
+}
+
+var (
+	modelsMutex sync.RWMutex
+	models      = make(map[string]Model)
+)
+
+func init() {
+	for _, model := range []Model{
+		FirstTouchpointModel{},
+		LastTouchpointModel{},
+		LinearModel{},
+		RepeatedLinearModel{},
+		TimeDecayModel{HalfLife: 7 * 24 * time.Hour},
+		PositionBasedModel{ModelName: "u_shaped", Weights: UShapedWeights},
+		PositionBasedModel{ModelName: "w_shaped", Weights: WShapedWeights(MiddleAnchor)},
+		ShapleyModel{},
+		OwenModel{},
+		BanzhafModel{},
+		CausalModel{},
+		LogisticRegressionModel{Options: LogisticRegressionOptions{Regularization: 1}},
+		MarkovModel{Order: 1},
+	} {
+		if err := RegisterModel(model); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterModel makes a model available under its name.
+// It returns an error if another model is already registered under the same name.
+func RegisterModel(model Model) error {
+	modelsMutex.Lock()
+	defer modelsMutex.Unlock()
+
+	name := model.Name()
+	if _, found := models[name]; found {
+		return fmt.Errorf("%w: %s", ErrDuplicateModel, name)
+	}
+	models[name] = model
+	return nil
+}
+
+// GetModel returns the model registered under the given name.
+func GetModel(name string) (Model, error) {
+	modelsMutex.RLock()
+	defer modelsMutex.RUnlock()
+
+	model, found := models[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownModel, name)
+	}
+	return model, nil
+}
+
+// GetModelNames returns the names of all registered models in sorted order.
+func GetModelNames() []string {
+	modelsMutex.RLock()
+	defer modelsMutex.RUnlock()
+
+	names := make([]string, 0, len(models))
+	for name := range models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
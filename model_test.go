@@ -0,0 +1,176 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func ExampleGetModel() {
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Touchpoint 1"},
+				Touchpoint{"Touchpoint 2"},
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Touchpoint 1"},
+			},
+			Value: *new(big.Float).SetFloat64(200.),
+		},
+	}
+
+	for _, name := range []string{"first_touchpoint", "last_touchpoint", "shapley"} {
+		model, err := GetModel(name)
+		if err != nil {
+			panic(err)
+		}
+		values, err := model.Attribute(contributions)
+		if err != nil {
+			panic(err)
+		}
+		first := values[Touchpoint{"Touchpoint 1"}]
+		second := values[Touchpoint{"Touchpoint 2"}]
+		fmt.Println(name, first.String(), second.String())
+	}
+	// Output:
+	// first_touchpoint 300 0
+	// last_touchpoint 200 100
+	// shapley 250 50
+}
+
+func intersectingValue(allContributions []ContributionSet) CharacteristicFunction {
+	return IntersectingValue(allContributions)
+}
+
+func TestModels(t *testing.T) {
+	contributions := contributionFixture()
+	contributionSets := getContributionSets(contributions)
+	halfLife := 7 * 24 * time.Hour
+
+	cases := []struct {
+		model    Model
+		getValue func(touchpoint Touchpoint) big.Float
+	}{
+		{FirstTouchpointModel{}, func(touchpoint Touchpoint) big.Float {
+			return GetFirstTouchpointValue(touchpoint, contributions)
+		}},
+		{LastTouchpointModel{}, func(touchpoint Touchpoint) big.Float {
+			return GetLastTouchpointValue(touchpoint, contributions)
+		}},
+		{LinearModel{}, func(touchpoint Touchpoint) big.Float {
+			return GetLinearValue(touchpoint, contributionSets)
+		}},
+		{RepeatedLinearModel{}, func(touchpoint Touchpoint) big.Float {
+			return GetRepeatedLinearValue(touchpoint, contributions)
+		}},
+		{TimeDecayModel{HalfLife: halfLife}, func(touchpoint Touchpoint) big.Float {
+			return GetTimeDecayValue(touchpoint, contributions, halfLife)
+		}},
+		{PositionBasedModel{ModelName: "u_shaped", Weights: UShapedWeights}, func(touchpoint Touchpoint) big.Float {
+			return GetPositionBasedValue(touchpoint, contributions, UShapedWeights)
+		}},
+		{ShapleyModel{}, func(touchpoint Touchpoint) big.Float {
+			return GetShapleyValue(touchpoint, contributionSets)
+		}},
+		{OwenModel{Groups: []Touchpoints{touchpointFixture()[:2]}}, func(touchpoint Touchpoint) big.Float {
+			return GetOwenValue(touchpoint, contributionSets, []Touchpoints{touchpointFixture()[:2]})
+		}},
+		{CharacteristicShapleyModel{ModelName: "shapley_any", Function: intersectingValue}, func(touchpoint Touchpoint) big.Float {
+			return GetShapleyValueWith(touchpoint, IntersectingValue(contributionSets))
+		}},
+		{BanzhafModel{}, func(touchpoint Touchpoint) big.Float {
+			return GetNormalizedBanzhafValues(contributionSets)[touchpoint]
+		}},
+		{CausalModel{}, func(touchpoint Touchpoint) big.Float {
+			return GetCausalValue(touchpoint, contributionSets)
+		}},
+		{CounterfactualModel{ModelName: "propensity", ConversionModel: ConversionModelFunc(additiveModel)}, func(touchpoint Touchpoint) big.Float {
+			return GetCounterfactualValues(ConversionModelFunc(additiveModel), contributions)[touchpoint]
+		}},
+		{CounterfactualModel{ModelName: "propensity_shapley", ConversionModel: ConversionModelFunc(additiveModel), Shapley: true}, func(touchpoint Touchpoint) big.Float {
+			return GetCounterfactualShapleyValues(ConversionModelFunc(additiveModel), contributions)[touchpoint]
+		}},
+		{MarkovModel{Order: 2}, func(touchpoint Touchpoint) big.Float {
+			return GetHigherOrderMarkovValue(touchpoint, contributions, 2)
+		}},
+	}
+
+	for _, c := range cases {
+		values, err := c.model.Attribute(contributions)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.model.Name(), err)
+			continue
+		}
+		if len(values) != len(GetAllTouchpoints(contributionSets)) {
+			t.Errorf("%s: got %d touchpoints want %d", c.model.Name(), len(values), len(GetAllTouchpoints(contributionSets)))
+		}
+		for touchpoint, value := range values {
+			expectedValue := c.getValue(touchpoint)
+			got, _ := value.Float64()
+			want, _ := expectedValue.Float64()
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("%s: %s: got %f want %f", c.model.Name(), touchpoint, got, want)
+			}
+		}
+
+		if _, err := c.model.Attribute(nil); !errors.Is(err, ErrEmptyInput) {
+			t.Errorf("%s: got %v want %v", c.model.Name(), err, ErrEmptyInput)
+		}
+	}
+
+	if _, err := (MarkovModel{}).Attribute(contributions); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("got %v want %v", err, ErrInvalidParameter)
+	}
+	if _, err := (TimeDecayModel{}).Attribute(contributions); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("got %v want %v", err, ErrInvalidParameter)
+	}
+	if _, err := (LogisticRegressionModel{Options: LogisticRegressionOptions{Regularization: -1}}).Attribute(contributions); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("got %v want %v", err, ErrInvalidParameter)
+	}
+	// every journey of the fixture converted, so the intercept diverges
+	if _, err := (LogisticRegressionModel{Options: LogisticRegressionOptions{Regularization: 1}}).Attribute(contributions); !errors.Is(err, ErrNotConverged) {
+		t.Errorf("got %v want %v", err, ErrNotConverged)
+	}
+	if _, err := (CharacteristicShapleyModel{}).Attribute(contributions); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("got %v want %v", err, ErrInvalidParameter)
+	}
+}
+
+func TestModelRegistry(t *testing.T) {
+	for _, name := range GetModelNames() {
+		model, err := GetModel(name)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if model.Name() != name {
+			t.Errorf("got %s want %s", model.Name(), name)
+		}
+	}
+
+	if _, err := GetModel("unknown"); !errors.Is(err, ErrUnknownModel) {
+		t.Errorf("got %v want %v", err, ErrUnknownModel)
+	}
+	if err := RegisterModel(LinearModel{}); !errors.Is(err, ErrDuplicateModel) {
+		t.Errorf("got %v want %v", err, ErrDuplicateModel)
+	}
+
+	model := PositionBasedModel{ModelName: "last_touchpoint_weights", Weights: func(length int) []float64 {
+		weights := make([]float64, length)
+		weights[length-1] = 1.
+		return weights
+	}}
+	if err := RegisterModel(model); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := GetModel(model.Name()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package attribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// A NormalizationRule rewrites raw touchpoint names.
+type NormalizationRule interface {
+	// Apply returns the rewritten name and true if the rule applies to the given name, and false otherwise.
+	Apply(name string) (string, bool)
+}
+
+// An ExactRule rewrites names found in its mapping.
+type ExactRule map[string]string
+
+// Apply looks up the given name in the mapping.
+func (rule ExactRule) Apply(name string) (string, bool) {
+	normalized, found := rule[name]
+	return normalized, found
+}
+
+// A RegexRule rewrites names matching its pattern.
+type RegexRule struct {
+	Pattern     *regexp.Regexp
+	Replacement string // replacement of the whole name; may refer to submatches as in regexp.Regexp.Expand
+}
+
+// Apply rewrites the given name if it matches the pattern.
+func (rule RegexRule) Apply(name string) (string, bool) {
+	submatches := rule.Pattern.FindStringSubmatchIndex(name)
+	if submatches == nil {
+		return "", false
+	}
+	return string(rule.Pattern.ExpandString(nil, rule.Replacement, name, submatches)), true
+}
+
+// A Normalizer rewrites touchpoint names according to an ordered list of rules, of which the first applicable one is
+// used.
+type Normalizer struct {
+	Lowercase bool                // lower-case names before applying any rules
+	Trim      bool                // trim surrounding whitespace from names before applying any rules
+	Rules     []NormalizationRule // rules in the order they are tried
+	Fallback  string              // name of touchpoints no rule applies to; if empty, such names are kept
+}
+
+// Normalize rewrites the name of a single touchpoint.
+func (normalizer Normalizer) Normalize(touchpoint Touchpoint) Touchpoint {
+	name := touchpoint.Name
+	if normalizer.Trim {
+		name = strings.TrimSpace(name)
+	}
+	if normalizer.Lowercase {
+		name = strings.ToLower(name)
+	}
+	for _, rule := range normalizer.Rules {
+		if normalized, ok := rule.Apply(name); ok {
+			return Touchpoint{Name: normalized}
+		}
+	}
+	if normalizer.Fallback != "" {
+		return Touchpoint{Name: normalizer.Fallback}
+	}
+	return Touchpoint{Name: name}
+}
+
+// NormalizeContributions returns copies of the given contributions with all touchpoint names rewritten.
+func (normalizer Normalizer) NormalizeContributions(allContributions []Contribution) []Contribution {
+	normalized := make([]Contribution, len(allContributions))
+	for index, contribution := range allContributions {
+		touchpoints := make(Touchpoints, len(contribution.Touchpoints))
+		for position, touchpoint := range contribution.Touchpoints {
+			touchpoints[position] = normalizer.Normalize(touchpoint)
+		}
+		contribution.Touchpoints = touchpoints
+		normalized[index] = contribution
+	}
+	return normalized
+}
+
+// NormalizeContributionSets returns copies of the given contributions with all touchpoint names rewritten.
+// Touchpoints that are rewritten to the same name are merged.
+func (normalizer Normalizer) NormalizeContributionSets(allContributions []ContributionSet) []ContributionSet {
+	normalized := make([]ContributionSet, len(allContributions))
+	for index, contribution := range allContributions {
+		touchpoints := make(map[Touchpoint]struct{}, len(contribution.Touchpoints))
+		for touchpoint := range contribution.Touchpoints {
+			touchpoints[normalizer.Normalize(touchpoint)] = struct{}{}
+		}
+		contribution.Touchpoints = touchpoints
+		normalized[index] = contribution
+	}
+	return normalized
+}
+
+// jsonNormalizer is the JSON configuration of a Normalizer.
+type jsonNormalizer struct {
+	Lowercase bool `json:"lowercase"`
+	Trim      bool `json:"trim"`
+	Rules     []struct {
+		Type        string            `json:"type"`
+		Mapping     map[string]string `json:"mapping"`
+		Pattern     string            `json:"pattern"`
+		Replacement string            `json:"replacement"`
+	} `json:"rules"`
+	Fallback string `json:"fallback"`
+}
+
+// LoadNormalizer reads a Normalizer from a JSON configuration such as
+//
+//	{
+//	  "lowercase": true,
+//	  "trim": true,
+//	  "rules": [
+//	    {"type": "exact", "mapping": {"paid_search": "paid search"}},
+//	    {"type": "regex", "pattern": "^google\\s*(/\\s*)?cpc$", "replacement": "paid search"}
+//	  ],
+//	  "fallback": "other"
+//	}
+func LoadNormalizer(reader io.Reader) (Normalizer, error) {
+	var config jsonNormalizer
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return Normalizer{}, fmt.Errorf("%w: %s", ErrInvalidConfig, err)
+	}
+
+	normalizer := Normalizer{
+		Lowercase: config.Lowercase,
+		Trim:      config.Trim,
+		Fallback:  config.Fallback,
+	}
+	for index, rule := range config.Rules {
+		switch rule.Type {
+		case "exact":
+			normalizer.Rules = append(normalizer.Rules, ExactRule(rule.Mapping))
+		case "regex":
+			pattern, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return Normalizer{}, fmt.Errorf("%w: rule %d: %s", ErrInvalidConfig, index, err)
+			}
+			normalizer.Rules = append(normalizer.Rules, RegexRule{Pattern: pattern, Replacement: rule.Replacement})
+		default:
+			return Normalizer{}, fmt.Errorf("%w: rule %d has unknown type %q", ErrInvalidConfig, index, rule.Type)
+		}
+	}
+
+	return normalizer, nil
+}
@@ -0,0 +1,94 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func ExampleLoadNormalizer() {
+	normalizer, err := LoadNormalizer(strings.NewReader(`{
+		"lowercase": true,
+		"trim": true,
+		"rules": [
+			{"type": "exact", "mapping": {"paid_search": "paid search"}},
+			{"type": "regex", "pattern": "^google\\s*(/\\s*)?cpc$", "replacement": "paid search"}
+		],
+		"fallback": "other"
+	}`))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, name := range []string{"Google CPC", "google / cpc", "paid_search", "Newsletter"} {
+		fmt.Println(normalizer.Normalize(Touchpoint{name}).Name)
+	}
+	// Output:
+	// paid search
+	// paid search
+	// paid search
+	// other
+}
+
+func TestNormalizerRuleOrder(t *testing.T) {
+	normalizer := Normalizer{
+		Rules: []NormalizationRule{
+			ExactRule{"google brand": "brand search"},
+			RegexRule{Pattern: regexp.MustCompile(`^google (\w+)$`), Replacement: "search ${1}"},
+			RegexRule{Pattern: regexp.MustCompile(`^google`), Replacement: "never used"},
+		},
+	}
+
+	for name, want := range map[string]string{
+		"google brand":  "brand search",
+		"google cpc":    "search cpc",
+		" Newsletter ":  " Newsletter ",
+		"google / shop": "never used",
+	} {
+		if got := normalizer.Normalize(Touchpoint{name}).Name; got != want {
+			t.Errorf("%q: got %q want %q", name, got, want)
+		}
+	}
+}
+
+func TestNormalizeContributionSets(t *testing.T) {
+	normalizer := Normalizer{
+		Lowercase: true,
+		Rules:     []NormalizationRule{ExactRule{"google cpc": "paid search", "paid_search": "paid search"}},
+	}
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: Touchpoints{Touchpoint{"Google CPC"}, Touchpoint{"Email"}, Touchpoint{"paid_search"}},
+			Value:       *new(big.Float).SetFloat64(100.),
+		},
+	}
+
+	normalized := normalizer.NormalizeContributions(contributions)
+	if got := normalized[0].Touchpoints.String(); got != "[{paid search} {email} {paid search}]" {
+		t.Errorf("got %s", got)
+	}
+	if contributions[0].Touchpoints[0].Name != "Google CPC" {
+		t.Errorf("input was modified")
+	}
+
+	sets := normalizer.NormalizeContributionSets(getContributionSets(contributions))
+	if len(sets[0].Touchpoints) != 2 {
+		t.Errorf("got %d touchpoints want 2", len(sets[0].Touchpoints))
+	}
+}
+
+func TestLoadNormalizerErrors(t *testing.T) {
+	for _, config := range []string{
+		`{"rules": [{"type": "fuzzy"}]}`,
+		`{"rules": [{"type": "regex", "pattern": "("}]}`,
+		`{"unknown": true}`,
+		`[`,
+	} {
+		if _, err := LoadNormalizer(strings.NewReader(config)); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: got %v want ErrInvalidConfig", config, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package attribution
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// An AttributionResult maps each touchpoint to the value attributed to it.
+type AttributionResult map[Touchpoint]big.Float
+
+// GetTouchpoints returns all touchpoints of the result in sorted order.
+func (result AttributionResult) GetTouchpoints() Touchpoints {
+	touchpoints := make(Touchpoints, 0, len(result))
+	for touchpoint := range result {
+		touchpoints = append(touchpoints, touchpoint)
+	}
+	sort.Sort(touchpoints)
+	return touchpoints
+}
+
+// GetTotal returns the summed value attributed to all touchpoints.
+func (result AttributionResult) GetTotal() big.Float {
+	total := new(big.Float)
+	// sum in a fixed order to obtain reproducible results
+	for _, touchpoint := range result.GetTouchpoints() {
+		value := result[touchpoint]
+		total.Add(total, &value)
+	}
+	return *total
+}
+
+// GetShares returns the percentage of the total value attributed to each touchpoint.
+// If the total value is zero, all shares are zero.
+func (result AttributionResult) GetShares() map[Touchpoint]big.Float {
+	total := result.GetTotal()
+	shares := make(map[Touchpoint]big.Float, len(result))
+	for touchpoint, value := range result {
+		share := new(big.Float)
+		if total.Sign() != 0 {
+			share.Quo(&value, &total)
+			share.Mul(share, new(big.Float).SetInt64(100))
+		}
+		shares[touchpoint] = *share
+	}
+	return shares
+}
+
+// GetRanking returns all touchpoints ordered by descending value.
+// Touchpoints with equal values are ordered by name.
+func (result AttributionResult) GetRanking() Touchpoints {
+	touchpoints := result.GetTouchpoints()
+	sort.SliceStable(touchpoints, func(i, j int) bool {
+		first := result[touchpoints[i]]
+		second := result[touchpoints[j]]
+		return first.Cmp(&second) > 0
+	})
+	return touchpoints
+}
+
+// GetTop returns the k touchpoints with the highest values, ordered as in GetRanking.
+// If the result holds fewer than k touchpoints, all of them are returned.
+func (result AttributionResult) GetTop(k int) Touchpoints {
+	ranking := result.GetRanking()
+	if k < 0 {
+		k = 0
+	}
+	if k < len(ranking) {
+		ranking = ranking[:k]
+	}
+	return ranking
+}
+
+// CheckEfficiency verifies that the values of the result add up to the total value of the given contributions, as
+// returned by GetTotalValue, up to the given absolute tolerance.
+// This is the efficiency axiom satisfied by Shapley values.
+func (result AttributionResult) CheckEfficiency(allContributions []ContributionSet, tolerance float64) error {
+	if !(tolerance >= 0) {
+		tolerance = 0
+	}
+	attributedValue := result.GetTotal()
+	totalValue := GetTotalValue(allContributions)
+
+	difference := new(big.Float).Sub(&attributedValue, &totalValue)
+	if difference.Abs(difference).Cmp(new(big.Float).SetFloat64(tolerance)) > 0 {
+		return fmt.Errorf("%w: attributed %s, total %s", ErrInefficientResult, attributedValue.String(), totalValue.String())
+	}
+	return nil
+}
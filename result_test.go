@@ -0,0 +1,105 @@
+package attribution
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func ExampleAttributionResult() {
+	contributions := []ContributionSet{
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 1"}: struct{}{},
+				Touchpoint{"Touchpoint 2"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 3"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(150.),
+		},
+	}
+	result := GetShapleyValues(contributions)
+	total := result.GetTotal()
+	shares := result.GetShares()
+
+	fmt.Println(total.String())
+	for _, touchpoint := range result.GetRanking() {
+		share := shares[touchpoint]
+		fmt.Println(touchpoint.Name, share.String())
+	}
+	fmt.Println(result.GetTop(1))
+	fmt.Println(result.CheckEfficiency(contributions, 1e-9))
+	// Output:
+	// 250
+	// Touchpoint 3 60
+	// Touchpoint 1 20
+	// Touchpoint 2 20
+	// [{Touchpoint 3}]
+	// <nil>
+}
+
+func TestAttributionResultGetTop(t *testing.T) {
+	result := AttributionResult{
+		Touchpoint{"b"}: *new(big.Float).SetFloat64(1.),
+		Touchpoint{"a"}: *new(big.Float).SetFloat64(1.),
+		Touchpoint{"c"}: *new(big.Float).SetFloat64(2.),
+	}
+
+	cases := []struct {
+		k    int
+		want string
+	}{
+		{-1, "[]"},
+		{0, "[]"},
+		{2, "[{c} {a}]"},
+		{5, "[{c} {a} {b}]"},
+	}
+	for _, c := range cases {
+		if got := result.GetTop(c.k).String(); got != c.want {
+			t.Errorf("k=%d: got %s want %s", c.k, got, c.want)
+		}
+	}
+}
+
+func TestAttributionResultGetShares(t *testing.T) {
+	result := AttributionResult{
+		Touchpoint{"a"}: *new(big.Float),
+	}
+
+	share := result.GetShares()[Touchpoint{"a"}]
+	if share.Sign() != 0 {
+		t.Errorf("got %s want 0", share.String())
+	}
+}
+
+func TestAttributionResultCheckEfficiency(t *testing.T) {
+	contributions := contributionFixture()
+	contributionSets := getContributionSets(contributions)
+
+	for _, model := range []Model{ShapleyModel{}, LinearModel{}, FirstTouchpointModel{}} {
+		result, err := model.Attribute(contributions)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", model.Name(), err)
+		}
+		// contributions without touchpoints can't be attributed to any touchpoint
+		err = result.CheckEfficiency(contributionSets, 1e-6)
+		if !errors.Is(err, ErrInefficientResult) {
+			t.Errorf("%s: got %v want %v", model.Name(), err, ErrInefficientResult)
+		}
+
+		var nonEmptyContributionSets []ContributionSet
+		for _, contribution := range contributionSets {
+			if len(contribution.Touchpoints) > 0 {
+				nonEmptyContributionSets = append(nonEmptyContributionSets, contribution)
+			}
+		}
+		if err := result.CheckEfficiency(nonEmptyContributionSets, 1e-6); err != nil {
+			t.Errorf("%s: unexpected error: %v", model.Name(), err)
+		}
+	}
+}
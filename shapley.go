@@ -1,9 +1,12 @@
 package attribution
 
 import (
-	"log"
+	"fmt"
+	"math"
 	"math/big"
+	"math/rand"
 	"sort"
+	"strings"
 )
 
 // GetTotalValue returns the summed value over all contributions.
@@ -17,6 +20,14 @@ func GetTotalValue(contributions []ContributionSet) big.Float {
 	return *value
 }
 
+// GetTotalValueChecked is like GetTotalValue, but returns an error for empty or non-finite input.
+func GetTotalValueChecked(contributions []ContributionSet) (big.Float, error) {
+	if err := validateContributionSets(contributions); err != nil {
+		return big.Float{}, err
+	}
+	return GetTotalValue(contributions), nil
+}
+
 // GetAllTouchpoints returns a list (without repetition) all touchpoints encountered in contributions.
 func GetAllTouchpoints(contributions []ContributionSet) Touchpoints {
 	seen := make(map[Touchpoint]struct{})
@@ -56,6 +67,20 @@ func GetCoalitionValue(coalition map[Touchpoint]struct{}, allContributions []Con
 	return *coalitionValue
 }
 
+// GetCoalitionValueChecked is like GetCoalitionValue, but returns an error for empty or non-finite input and for
+// coalitions containing touchpoints that don't occur in any contribution.
+func GetCoalitionValueChecked(coalition map[Touchpoint]struct{}, allContributions []ContributionSet) (big.Float, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	for touchpoint := range coalition {
+		if err := validateSetTouchpoint(touchpoint, allContributions); err != nil {
+			return big.Float{}, err
+		}
+	}
+	return GetCoalitionValue(coalition, allContributions), nil
+}
+
 // findTouchpoint attempts to find a given touchpoint in a slice of touchpoints.
 // If the search is successful, return the first indice where the touchpoint occured in the first coordinate and true in the second coordinate.
 // Otherwise, return (-1, false)
@@ -69,67 +94,465 @@ func findTouchpoint(touchpoint Touchpoint, slice []Touchpoint) (int, bool) {
 }
 
 // GetShapleyValue returns the (unordered) Shapley value of a given touchpoint over all provided contributions.
+// A touchpoint that doesn't occur in any contribution has a Shapley value of zero.
 // For a concise introduction to Shapley values, see https://christophm.github.io/interpretable-ml-book/shapley.html
 func GetShapleyValue(touchpoint Touchpoint, allContributions []ContributionSet) big.Float {
-	shapleyValue := new(big.Float)
-	allTouchpoints := GetAllTouchpoints(allContributions)
-	touchpointIndex, found := findTouchpoint(touchpoint, allTouchpoints)
+	return GetShapleyValueWith(touchpoint, ContainedValue(allContributions))
+}
+
+// GetShapleyValueWith returns the Shapley value of a given touchpoint in the game defined by the given characteristic
+// function.
+// A touchpoint that doesn't take part in the game has a Shapley value of zero.
+func GetShapleyValueWith(touchpoint Touchpoint, function CharacteristicFunction) big.Float {
+	if _, ok := function.(harsanyiDivider); ok {
+		return GetShapleyValuesWith(function)[touchpoint]
+	}
+
+	table := newCharacteristicTable(function)
+	index, found := table.indices[touchpoint]
 	if !found {
-		log.Fatal("Illegal touchpoint!")
+		// a touchpoint that never contributed is a null player
+		return big.Float{}
+	}
+
+	return table.getSemivalue(index, getShapleyWeights(len(table.touchpoints)))
+}
+
+// GetShapleyValueChecked is like GetShapleyValue, but returns an error for empty or non-finite input and for
+// touchpoints that don't occur in any contribution.
+func GetShapleyValueChecked(touchpoint Touchpoint, allContributions []ContributionSet) (big.Float, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateSetTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	return GetShapleyValue(touchpoint, allContributions), nil
+}
+
+// GetShapleyValues returns the (unordered) Shapley values of all touchpoints encountered in the provided contributions.
+// The values are derived from the Harsanyi dividends of the game, so the runtime is linear in the total length of all
+// contributions rather than exponential in the number of touchpoints.
+func GetShapleyValues(allContributions []ContributionSet) AttributionResult {
+	return GetShapleyValuesWith(ContainedValue(allContributions))
+}
+
+// GetShapleyValuesWith returns the Shapley values of all touchpoints taking part in the game defined by the given
+// characteristic function.
+// If the function provides its Harsanyi dividends directly, as ContainedValue and ConversionRateValue do, the Shapley
+// values are computed from them in time linear in the total length of all contributions. Otherwise, the function is
+// evaluated once for every coalition.
+func GetShapleyValuesWith(function CharacteristicFunction) AttributionResult {
+	if divider, ok := function.(harsanyiDivider); ok {
+		shapleyValues := GetShapleyValuesFromDividends(divider.getHarsanyiDividends())
+		// touchpoints which only occur in coalitions with a dividend of zero are null players
+		for _, touchpoint := range function.GetTouchpoints() {
+			if _, found := shapleyValues[touchpoint]; !found {
+				shapleyValues[touchpoint] = big.Float{}
+			}
+		}
+		return shapleyValues
+	}
+
+	table := newCharacteristicTable(function)
+	weights := getShapleyWeights(len(table.touchpoints))
+	shapleyValues := make(AttributionResult, len(table.touchpoints))
+
+	for index, touchpoint := range table.touchpoints {
+		shapleyValues[touchpoint] = table.getSemivalue(uint(index), weights)
+	}
+
+	return shapleyValues
+}
+
+// GetShapleyValuesChecked is like GetShapleyValues, but returns an error for empty or non-finite input.
+func GetShapleyValuesChecked(allContributions []ContributionSet) (AttributionResult, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return nil, err
+	}
+	return GetShapleyValues(allContributions), nil
+}
+
+// GetOwenValue returns the Owen value of a given touchpoint over all provided contributions, where touchpoints are
+// partitioned into the given groups.
+// A touchpoint that doesn't occur in any contribution has an Owen value of zero.
+func GetOwenValue(touchpoint Touchpoint, allContributions []ContributionSet, groups []Touchpoints) big.Float {
+	return GetOwenValues(allContributions, groups)[touchpoint]
+}
+
+// GetOwenValueChecked is like GetOwenValue, but returns an error for empty or non-finite input, for touchpoints that
+// don't occur in any contribution and for groups that overlap.
+func GetOwenValueChecked(touchpoint Touchpoint, allContributions []ContributionSet, groups []Touchpoints) (big.Float, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return big.Float{}, err
 	}
-	allTouchpoints[touchpointIndex] = allTouchpoints[len(allTouchpoints)-1]
-	allTouchpoints[len(allTouchpoints)-1] = touchpoint
-	powerset := getPowerSetIndices(uint(len(allTouchpoints) - 1))
+	if err := validateSetTouchpoint(touchpoint, allContributions); err != nil {
+		return big.Float{}, err
+	}
+	if err := validateGroups(groups); err != nil {
+		return big.Float{}, err
+	}
+	return GetOwenValue(touchpoint, allContributions, groups), nil
+}
 
-	for _, subset := range powerset {
-		coalition := make(map[Touchpoint]struct{}, len(subset))
-		coalitionSize := int64(0)
-		for _, index := range subset {
-			coalition[allTouchpoints[index]] = struct{}{}
-			coalitionSize++
+// GetOwenValues returns the Owen values of all touchpoints encountered in the provided contributions, where touchpoints
+// are partitioned into the given groups. Touchpoints not assigned to any group form a group of their own; if a
+// touchpoint is assigned to several groups, only the first one counts.
+// The value of every group is its Shapley value in the game played among groups, which is then split among its members
+// by their Shapley values in the game in which all other groups act as single players. With singleton groups or a
+// single group, Owen values coincide with Shapley values.
+// Like GetShapleyValues, the values are derived from the Harsanyi dividends of the game: every group taking part in a
+// contribution receives an equal share of its value, which is split evenly among the group's members taking part. The
+// runtime is therefore linear in the total length of all contributions.
+func GetOwenValues(allContributions []ContributionSet, groups []Touchpoints) AttributionResult {
+	partition := getPartition(GetAllTouchpoints(allContributions), groups)
+	groupIndices := make(map[Touchpoint]int)
+	owenValues := make(AttributionResult)
+	for groupIndex, group := range partition {
+		for _, touchpoint := range group {
+			groupIndices[touchpoint] = groupIndex
+			owenValues[touchpoint] = big.Float{}
 		}
+	}
 
-		if _, ok := coalition[touchpoint]; ok {
-			log.Fatal("This should never happen!")
+	for _, dividend := range GetHarsanyiDividends(allContributions) {
+		// numberMembers[groupIndex] is the number of members of the group taking part in the dividend's coalition
+		numberMembers := make(map[int]int64)
+		for touchpoint := range dividend.Coalition {
+			numberMembers[groupIndices[touchpoint]]++
 		}
-		coalitionValue := GetCoalitionValue(coalition, allContributions)
+		numberGroups := int64(len(numberMembers))
+		for touchpoint := range dividend.Coalition {
+			share := new(big.Float).SetInt64(numberGroups * numberMembers[groupIndices[touchpoint]])
+			share.Quo(&dividend.Value, share)
+			owenValue := owenValues[touchpoint]
+			owenValue.Add(&owenValue, share)
+			owenValues[touchpoint] = owenValue
+		}
+	}
+
+	return owenValues
+}
 
-		coalition[touchpoint] = struct{}{}
-		addedCoalitionValue := GetCoalitionValue(coalition, allContributions)
-		addedCoalitionValue.Sub(&addedCoalitionValue, &coalitionValue)
-		nominator := new(big.Int).MulRange(1, coalitionSize)
-		nominator.Mul(nominator, new(big.Int).MulRange(1, int64(len(allTouchpoints))-coalitionSize-1))
-		denominator := new(big.Int).MulRange(1, int64(len(allTouchpoints)))
-		scalingFactor := new(big.Float)
-		scalingFactor.Quo(new(big.Float).SetInt(nominator), new(big.Float).SetInt(denominator))
+// GetOwenValuesChecked is like GetOwenValues, but returns an error for empty or non-finite input and for groups that
+// overlap.
+func GetOwenValuesChecked(allContributions []ContributionSet, groups []Touchpoints) (AttributionResult, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return nil, err
+	}
+	if err := validateGroups(groups); err != nil {
+		return nil, err
+	}
+	return GetOwenValues(allContributions, groups), nil
+}
 
-		addedShapleyValue := new(big.Float)
-		addedShapleyValue.Mul(scalingFactor, &addedCoalitionValue)
-		shapleyValue.Add(shapleyValue, addedShapleyValue)
+// getPartition restricts the given groups to the given touchpoints and adds a singleton group for every touchpoint not
+// assigned to any group. Touchpoints assigned to several groups are kept in the first one only, and empty groups are
+// dropped. The members of every group are sorted.
+func getPartition(touchpoints Touchpoints, groups []Touchpoints) []Touchpoints {
+	unassigned := make(map[Touchpoint]struct{}, len(touchpoints))
+	for _, touchpoint := range touchpoints {
+		unassigned[touchpoint] = struct{}{}
+	}
 
+	var partition []Touchpoints
+	for _, group := range groups {
+		var members Touchpoints
+		for _, touchpoint := range group {
+			if _, found := unassigned[touchpoint]; found {
+				delete(unassigned, touchpoint)
+				members = append(members, touchpoint)
+			}
+		}
+		if len(members) > 0 {
+			sort.Sort(members)
+			partition = append(partition, members)
+		}
+	}
+	for _, touchpoint := range touchpoints {
+		if _, found := unassigned[touchpoint]; found {
+			partition = append(partition, Touchpoints{touchpoint})
+		}
 	}
-	return *shapleyValue
+
+	return partition
+}
+
+// An OrderedShapleyValue represents the ordered Shapley value of a touchpoint, broken down by the positions at which the
+// touchpoint occurred.
+type OrderedShapleyValue struct {
+	Value            big.Float   // total credit of the touchpoint
+	Positions        []big.Float // credit by position, counted from the first touchpoint of a path
+	PositionsFromEnd []big.Float // credit by position, counted from the last touchpoint of a path
 }
 
-// getPowerSetIndices provides the powerset of {0, 1, .., size - 1}.
-// This can be used to iterate over arbitary powersets by using this result as an index.
-func getPowerSetIndices(size uint) [][]uint {
-	if size < 1 {
-		return [][]uint{[]uint{}}
+func (orderedShapleyValue OrderedShapleyValue) String() string {
+	positions := make([]string, len(orderedShapleyValue.Positions))
+	for index := range orderedShapleyValue.Positions {
+		positions[index] = orderedShapleyValue.Positions[index].String()
 	}
-	powerSetSize := 2 << (size - 1)
-	powerset := make([][]uint, 0, powerSetSize)
+	return fmt.Sprintf("{%s [%s]}", orderedShapleyValue.Value.String(), strings.Join(positions, " "))
+}
+
+// GetOrderedShapleyValues returns the ordered Shapley values of all touchpoints encountered in the provided
+// contributions, as introduced in Zhao et al., "Shapley Value Methods for Attribution Modeling in Online Advertising".
+// As with GetShapleyValue, the value of each contribution is split equally among its distinct touchpoints. The share of
+// a touchpoint is then credited to the positions at which it occurred, split equally among repeated occurrences.
+// Summing over all positions therefore yields the unordered Shapley value.
+func GetOrderedShapleyValues(allContributions []Contribution) map[Touchpoint]OrderedShapleyValue {
+	orderedShapleyValues := make(map[Touchpoint]OrderedShapleyValue)
+
+	for _, contribution := range allContributions {
+		length := len(contribution.Touchpoints)
+		occurrences := make(map[Touchpoint]int)
+		for _, touchpoint := range contribution.Touchpoints {
+			occurrences[touchpoint]++
+		}
+
+		for position, touchpoint := range contribution.Touchpoints {
+			// distribute value equally among all distinct contributors and their occurrences
+			addedValue := new(big.Float).SetInt64(int64(len(occurrences) * occurrences[touchpoint]))
+			addedValue.Quo(&contribution.Value, addedValue)
 
-	index := 0
-	for index < powerSetSize {
-		var subSet []uint
-		for i := uint(0); i < size; i++ {
-			if index&(1<<i) > 0 {
-				subSet = append(subSet, i)
+			orderedShapleyValue := orderedShapleyValues[touchpoint]
+			for len(orderedShapleyValue.Positions) < length {
+				orderedShapleyValue.Positions = append(orderedShapleyValue.Positions, big.Float{})
+				orderedShapleyValue.PositionsFromEnd = append(orderedShapleyValue.PositionsFromEnd, big.Float{})
 			}
+			orderedShapleyValue.Value.Add(&orderedShapleyValue.Value, addedValue)
+			orderedShapleyValue.Positions[position].Add(&orderedShapleyValue.Positions[position], addedValue)
+			fromEnd := length - position - 1
+			orderedShapleyValue.PositionsFromEnd[fromEnd].Add(&orderedShapleyValue.PositionsFromEnd[fromEnd], addedValue)
+			orderedShapleyValues[touchpoint] = orderedShapleyValue
 		}
-		powerset = append(powerset, subSet)
-		index++
 	}
-	return powerset
+
+	return orderedShapleyValues
+}
+
+// GetOrderedShapleyValuesChecked is like GetOrderedShapleyValues, but returns an error for empty or non-finite input.
+func GetOrderedShapleyValuesChecked(allContributions []Contribution) (map[Touchpoint]OrderedShapleyValue, error) {
+	if err := validateContributions(allContributions); err != nil {
+		return nil, err
+	}
+	return GetOrderedShapleyValues(allContributions), nil
+}
+
+// defaultShapleySamples is the number of sampled orderings used by GetApproximateShapleyValues if no sample budget
+// is provided.
+const defaultShapleySamples = 10000
+
+// minShapleySamples is the minimal number of sampled orderings before a target standard error is considered reached.
+const minShapleySamples = 30
+
+// ShapleySamplingOptions configures the permutation sampling in GetApproximateShapleyValues and
+// GetApproximateShapleyValuesWith.
+type ShapleySamplingOptions struct {
+	Samples             int     // maximal number of sampled orderings; defaults to 10000 if not positive
+	TargetStandardError float64 // stop sampling once all standard errors are below this value; ignored if not positive
+	Seed                int64   // seed of the random number generator
+}
+
+// A ShapleyEstimate represents an approximated Shapley value together with the standard error of the approximation.
+type ShapleyEstimate struct {
+	Value         big.Float
+	StandardError big.Float
+	Samples       int // number of sampled orderings the estimate is based on
+}
+
+func (estimate ShapleyEstimate) String() string {
+	return fmt.Sprintf("{%s ± %s}", estimate.Value.String(), estimate.StandardError.String())
+}
+
+// GetApproximateShapleyValues estimates the (unordered) Shapley values of all touchpoints encountered in the provided
+// contributions by sampling random orderings of the touchpoints and averaging each touchpoint's marginal contribution.
+// Since GetShapleyValues computes the exact values in linear time, the estimates mainly serve to validate sampling
+// against a known answer; see GetApproximateShapleyValuesWith for games without a fast exact solution.
+func GetApproximateShapleyValues(allContributions []ContributionSet, options ShapleySamplingOptions) map[Touchpoint]ShapleyEstimate {
+	return GetApproximateShapleyValuesWith(ContainedValue(allContributions), options)
+}
+
+// GetApproximateShapleyValuesWith estimates the Shapley values of all touchpoints taking part in the game defined by
+// the given characteristic function by sampling random orderings of the touchpoints and averaging each touchpoint's
+// marginal contribution.
+// Every sampled ordering evaluates the function once per touchpoint. As long as a single evaluation is cheap, as for
+// IntersectingValue, CoalitionValues or ConversionProbabilityValue, whose evaluations take a pass over their
+// contributions or a table lookup, the runtime thus doesn't grow exponentially with the number of touchpoints, unlike
+// that of GetShapleyValuesWith for functions without Harsanyi dividends.
+// No estimates are returned if the marginal contributions vary too widely to be sampled as float64 values.
+func GetApproximateShapleyValuesWith(function CharacteristicFunction, options ShapleySamplingOptions) map[Touchpoint]ShapleyEstimate {
+	estimates, err := getApproximateShapleyValues(function, options)
+	if err != nil {
+		return make(map[Touchpoint]ShapleyEstimate)
+	}
+	return estimates
+}
+
+// getApproximateShapleyValues is like GetApproximateShapleyValuesWith, but returns an error if the marginal
+// contributions can't be sampled as finite float64 values.
+func getApproximateShapleyValues(function CharacteristicFunction, options ShapleySamplingOptions) (map[Touchpoint]ShapleyEstimate, error) {
+	if contributions, ok := function.(ContainedValue); ok {
+		sampler, exponent := newContainedSampler(contributions)
+		return sampleShapleyValues(contributions.GetTouchpoints(), options, sampler, exponent)
+	}
+
+	touchpoints := function.GetTouchpoints()
+	allTouchpoints := make(map[Touchpoint]struct{}, len(touchpoints))
+	for _, touchpoint := range touchpoints {
+		allTouchpoints[touchpoint] = struct{}{}
+	}
+	emptyValue := function.GetValue(map[Touchpoint]struct{}{})
+	totalValue := function.GetValue(allTouchpoints)
+	// scale the marginal contributions by the larger of the two values to keep large but finite values representable
+	exponent := emptyValue.MantExp(nil)
+	if totalExponent := totalValue.MantExp(nil); totalExponent > exponent {
+		exponent = totalExponent
+	}
+
+	sampler := func(order []int, marginalContributions []float64) {
+		coalition := make(map[Touchpoint]struct{}, len(touchpoints))
+		previousValue := emptyValue
+		for _, index := range order {
+			coalition[touchpoints[index]] = struct{}{}
+			value := function.GetValue(coalition)
+			marginalContribution := new(big.Float).Sub(&value, &previousValue)
+			marginalContributions[index], _ = marginalContribution.SetMantExp(marginalContribution, -exponent).Float64()
+			previousValue = value
+		}
+	}
+	return sampleShapleyValues(touchpoints, options, sampler, exponent)
+}
+
+// A shapleySampler sets the marginal contribution of every touchpoint when the touchpoints join in the given order.
+type shapleySampler func(order []int, marginalContributions []float64)
+
+// newContainedSampler returns a shapleySampler for the game of ContainedValue, which only needs to keep track of the
+// contributions completed by each touchpoint instead of evaluating whole coalitions.
+// The sampled marginal contributions are scaled by 2^-exponent, which keeps them between -1 and 1.
+func newContainedSampler(allContributions ContainedValue) (shapleySampler, int) {
+	touchpointIndices := make(map[Touchpoint]int)
+	for index, touchpoint := range allContributions.GetTouchpoints() {
+		touchpointIndices[touchpoint] = index
+	}
+	absoluteValue := new(big.Float)
+	for _, contribution := range allContributions {
+		absoluteValue.Add(absoluteValue, new(big.Float).Abs(&contribution.Value))
+	}
+	exponent := absoluteValue.MantExp(nil)
+
+	// containedIn[index] lists all contributions the touchpoint with the given index took part in
+	containedIn := make([][]int, len(touchpointIndices))
+	contributionSizes := make([]int, len(allContributions))
+	contributionValues := make([]float64, len(allContributions))
+	for contributionIndex, contribution := range allContributions {
+		for touchpoint := range contribution.Touchpoints {
+			index := touchpointIndices[touchpoint]
+			containedIn[index] = append(containedIn[index], contributionIndex)
+		}
+		contributionSizes[contributionIndex] = len(contribution.Touchpoints)
+		contributionValues[contributionIndex], _ = new(big.Float).SetMantExp(&contribution.Value, -exponent).Float64()
+	}
+	missingTouchpoints := make([]int, len(allContributions))
+
+	return func(order []int, marginalContributions []float64) {
+		copy(missingTouchpoints, contributionSizes)
+		for _, index := range order {
+			// the marginal contribution consists of all contributions that are completed by adding this touchpoint
+			marginalContribution := 0.
+			for _, contributionIndex := range containedIn[index] {
+				missingTouchpoints[contributionIndex]--
+				if missingTouchpoints[contributionIndex] == 0 {
+					marginalContribution += contributionValues[contributionIndex]
+				}
+			}
+			marginalContributions[index] = marginalContribution
+		}
+	}, exponent
+}
+
+// sampleShapleyValues estimates the Shapley values of the given touchpoints from the marginal contributions of
+// randomly sampled orderings, which the sampler scales by 2^-exponent.
+// An error is returned if a marginal contribution, mean or standard error isn't finite.
+func sampleShapleyValues(touchpoints Touchpoints, options ShapleySamplingOptions, sampler shapleySampler, exponent int) (map[Touchpoint]ShapleyEstimate, error) {
+	numberTouchpoints := len(touchpoints)
+	estimates := make(map[Touchpoint]ShapleyEstimate, numberTouchpoints)
+	if numberTouchpoints == 0 {
+		return estimates, nil
+	}
+
+	samples := options.Samples
+	if samples <= 0 {
+		samples = defaultShapleySamples
+	}
+	random := rand.New(rand.NewSource(options.Seed))
+	// running mean and sum of squared deviations of every touchpoint's marginal contribution (Welford's algorithm)
+	means := make([]float64, numberTouchpoints)
+	squaredDeviations := make([]float64, numberTouchpoints)
+	marginalContributions := make([]float64, numberTouchpoints)
+
+	sample := 0
+	for sample < samples {
+		sample++
+		sampler(random.Perm(numberTouchpoints), marginalContributions)
+		for index, marginalContribution := range marginalContributions {
+			delta := marginalContribution - means[index]
+			means[index] += delta / float64(sample)
+			squaredDeviations[index] += delta * (marginalContribution - means[index])
+			if math.IsInf(squaredDeviations[index], 0) || math.IsNaN(squaredDeviations[index]) {
+				return nil, fmt.Errorf("%w: marginal contribution of %s exceeds the float64 range", ErrNonFiniteValue, touchpoints[index].Name)
+			}
+		}
+
+		if options.TargetStandardError > 0 && sample >= minShapleySamples {
+			reached := true
+			for index := range means {
+				if math.Ldexp(getStandardError(squaredDeviations[index], sample), exponent) > options.TargetStandardError {
+					reached = false
+					break
+				}
+			}
+			if reached {
+				break
+			}
+		}
+	}
+
+	for index, touchpoint := range touchpoints {
+		estimate := ShapleyEstimate{Samples: sample}
+		estimate.Value.SetFloat64(means[index])
+		estimate.Value.SetMantExp(&estimate.Value, exponent)
+		estimate.StandardError.SetFloat64(getStandardError(squaredDeviations[index], sample))
+		if !estimate.StandardError.IsInf() {
+			estimate.StandardError.SetMantExp(&estimate.StandardError, exponent)
+		}
+		estimates[touchpoint] = estimate
+	}
+
+	return estimates, nil
+}
+
+// GetApproximateShapleyValuesChecked is like GetApproximateShapleyValues, but returns an error for empty input and for
+// values whose absolute values don't add up to a finite float64 value.
+func GetApproximateShapleyValuesChecked(allContributions []ContributionSet, options ShapleySamplingOptions) (map[Touchpoint]ShapleyEstimate, error) {
+	if err := validateContributionSets(allContributions); err != nil {
+		return nil, err
+	}
+	// marginal contributions are bounded by the sum of absolute values
+	absoluteValue := new(big.Float)
+	for _, contribution := range allContributions {
+		absoluteValue.Add(absoluteValue, new(big.Float).Abs(&contribution.Value))
+	}
+	if value, _ := absoluteValue.Float64(); math.IsInf(value, 0) {
+		return nil, fmt.Errorf("%w: absolute values add up to %s", ErrNonFiniteValue, absoluteValue.String())
+	}
+	return getApproximateShapleyValues(ContainedValue(allContributions), options)
+}
+
+// getStandardError returns the standard error of a sample mean given the sum of squared deviations from the mean.
+func getStandardError(squaredDeviations float64, samples int) float64 {
+	if samples < 2 {
+		return math.Inf(1)
+	}
+	variance := squaredDeviations / float64(samples-1)
+	return math.Sqrt(variance / float64(samples))
 }
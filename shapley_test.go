@@ -1,7 +1,9 @@
 package attribution
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"testing"
 )
@@ -217,8 +219,456 @@ func TestGetShapleyValue(t *testing.T) {
 	}
 }
 
+func ExampleGetShapleyValues() {
+	contributions := []ContributionSet{
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 1"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 1"}: struct{}{},
+				Touchpoint{"Touchpoint 2"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(200.),
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 1"}: struct{}{},
+				Touchpoint{"Touchpoint 3"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(300.),
+		},
+	}
+	shapleyValues := GetShapleyValues(contributions)
+
+	for _, touchpoint := range GetAllTouchpoints(contributions) {
+		shapleyValue := shapleyValues[touchpoint]
+		fmt.Println(touchpoint.Name, shapleyValue.String())
+	}
+	// Output:
+	// Touchpoint 1 350
+	// Touchpoint 2 100
+	// Touchpoint 3 150
+}
+
+func TestGetShapleyValues(t *testing.T) {
+	contributions := contributionSetFixture()
+	shapleyValues := GetShapleyValues(contributions)
+
+	for _, touchpoint := range GetAllTouchpoints(contributions) {
+		shapleyValue := shapleyValues[touchpoint]
+		expectedValue := GetShapleyValue(touchpoint, contributions)
+
+		got, _ := shapleyValue.Float64()
+		want, _ := expectedValue.Float64()
+
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: got %f want %f", touchpoint, got, want)
+		}
+	}
+}
+
+func ExampleGetOwenValues() {
+	contributions := []ContributionSet{
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Campaign 1"}: struct{}{},
+				Touchpoint{"Campaign 2"}: struct{}{},
+				Touchpoint{"Search"}:     struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(120.),
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Campaign 1"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(10.),
+		},
+	}
+	groups := []Touchpoints{
+		Touchpoints{Touchpoint{"Campaign 1"}, Touchpoint{"Campaign 2"}},
+	}
+	owenValues := GetOwenValues(contributions, groups)
+	shapleyValues := GetShapleyValues(contributions)
+
+	for _, touchpoint := range GetAllTouchpoints(contributions) {
+		owenValue := owenValues[touchpoint]
+		shapleyValue := shapleyValues[touchpoint]
+		fmt.Println(touchpoint.Name, owenValue.String(), shapleyValue.String())
+	}
+	// Output:
+	// Campaign 1 40 50
+	// Campaign 2 30 40
+	// Search 60 40
+}
+
+func TestGetOwenValuesWithTrivialPartitions(t *testing.T) {
+	contributions := contributionSetFixture()
+	touchpoints := GetAllTouchpoints(contributions)
+	shapleyValues := GetShapleyValues(contributions)
+
+	var singletons []Touchpoints
+	for _, touchpoint := range touchpoints {
+		singletons = append(singletons, Touchpoints{touchpoint})
+	}
+
+	for _, groups := range [][]Touchpoints{nil, singletons, []Touchpoints{touchpoints}} {
+		owenValues := GetOwenValues(contributions, groups)
+		for _, touchpoint := range touchpoints {
+			owenValue := owenValues[touchpoint]
+			shapleyValue := shapleyValues[touchpoint]
+
+			got, _ := owenValue.Float64()
+			want, _ := shapleyValue.Float64()
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("%d groups: %s: got %f want %f", len(groups), touchpoint, got, want)
+			}
+		}
+	}
+}
+
+func TestGetOwenValuesGroupTotals(t *testing.T) {
+	var contributions []ContributionSet
+	for _, contribution := range contributionSetFixture() {
+		// contributions without touchpoints can't be attributed to any touchpoint
+		if len(contribution.Touchpoints) > 0 {
+			contributions = append(contributions, contribution)
+		}
+	}
+	touchpoints := GetAllTouchpoints(contributions)
+	groups := []Touchpoints{touchpoints[:2], touchpoints[2:]}
+	owenValues := GetOwenValues(contributions, groups)
+
+	if err := owenValues.CheckEfficiency(contributions, 1e-9); err != nil {
+		t.Error(err)
+	}
+
+	// the total value of every group is its Shapley value in the game played among groups
+	var groupContributions []ContributionSet
+	for _, contribution := range contributions {
+		groupContribution := contribution
+		groupContribution.Touchpoints = make(map[Touchpoint]struct{})
+		for touchpoint := range contribution.Touchpoints {
+			if _, found := findTouchpoint(touchpoint, groups[0]); found {
+				groupContribution.Touchpoints[Touchpoint{"Group 1"}] = struct{}{}
+			} else {
+				groupContribution.Touchpoints[Touchpoint{"Group 2"}] = struct{}{}
+			}
+		}
+		groupContributions = append(groupContributions, groupContribution)
+	}
+	groupValues := GetShapleyValues(groupContributions)
+
+	for index, group := range groups {
+		total := new(big.Float)
+		for _, touchpoint := range group {
+			owenValue := owenValues[touchpoint]
+			total.Add(total, &owenValue)
+		}
+		groupValue := groupValues[Touchpoint{fmt.Sprintf("Group %d", index+1)}]
+
+		got, _ := total.Float64()
+		want, _ := groupValue.Float64()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("group %d: got %f want %f", index+1, got, want)
+		}
+	}
+}
+
+func TestOwenModelManyFlatTouchpoints(t *testing.T) {
+	// flat names put every touchpoint into a group of its own, which mustn't require a table of all coalitions
+	var contributions []Contribution
+	for index := 0; index < 70; index++ {
+		contributions = append(contributions, Contribution{
+			Touchpoints: Touchpoints{Touchpoint{fmt.Sprintf("channel %d", index)}, Touchpoint{fmt.Sprintf("channel %d", (index+1)%70)}},
+			Value:       *big.NewFloat(float64(index + 1)),
+		})
+	}
+
+	owenValues, err := OwenModel{}.Attribute(contributions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shapleyValues := GetShapleyValues(getContributionSets(contributions))
+	if len(owenValues) != len(shapleyValues) {
+		t.Errorf("got %d values want %d", len(owenValues), len(shapleyValues))
+	}
+	for touchpoint, shapleyValue := range shapleyValues {
+		owenValue := owenValues[touchpoint]
+		if owenValue.Cmp(&shapleyValue) != 0 {
+			t.Errorf("%s: got %s want %s", touchpoint.Name, owenValue.String(), shapleyValue.String())
+		}
+	}
+}
+
+func TestGetOwenValuesChecked(t *testing.T) {
+	contributions := contributionSetFixture()
+	touchpoints := GetAllTouchpoints(contributions)
+
+	if _, err := GetOwenValuesChecked(nil, nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("got %v want ErrEmptyInput", err)
+	}
+	overlapping := []Touchpoints{touchpoints[:2], touchpoints[1:]}
+	if _, err := GetOwenValuesChecked(contributions, overlapping); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("got %v want ErrInvalidParameter", err)
+	}
+	if _, err := GetOwenValueChecked(Touchpoint{"unknown"}, contributions, nil); !errors.Is(err, ErrUnknownTouchpoint) {
+		t.Errorf("got %v want ErrUnknownTouchpoint", err)
+	}
+	if _, err := GetOwenValueChecked(touchpoints[0], contributions, []Touchpoints{touchpoints}); err != nil {
+		t.Error(err)
+	}
+}
+
+func ExampleGetApproximateShapleyValues() {
+	contributions := []ContributionSet{
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 1"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 2"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(200.),
+		},
+	}
+	estimates := GetApproximateShapleyValues(contributions, ShapleySamplingOptions{Samples: 100, Seed: 42})
+
+	for _, touchpoint := range GetAllTouchpoints(contributions) {
+		fmt.Println(touchpoint.Name, estimates[touchpoint])
+	}
+	// Output:
+	// Touchpoint 1 {100 ± 0}
+	// Touchpoint 2 {200 ± 0}
+}
+
+func TestGetApproximateShapleyValues(t *testing.T) {
+	contributions := contributionSetFixture()
+	shapleyValues := GetShapleyValues(contributions)
+	estimates := GetApproximateShapleyValues(contributions, ShapleySamplingOptions{Samples: 2000, Seed: 1})
+
+	for touchpoint, shapleyValue := range shapleyValues {
+		estimate := estimates[touchpoint]
+		if estimate.Samples != 2000 {
+			t.Errorf("%s: got %d samples want %d", touchpoint, estimate.Samples, 2000)
+		}
+		got, _ := estimate.Value.Float64()
+		want, _ := shapleyValue.Float64()
+		standardError, _ := estimate.StandardError.Float64()
+
+		if math.Abs(got-want) > 5*standardError+1e-9 {
+			t.Errorf("%s: got %f ± %f want %f", touchpoint, got, standardError, want)
+		}
+	}
+}
+
+func TestGetApproximateShapleyValuesLargeValues(t *testing.T) {
+	a, b := Touchpoint{"a"}, Touchpoint{"b"}
+	contributions := []ContributionSet{
+		ContributionSet{Touchpoints: map[Touchpoint]struct{}{a: struct{}{}}},
+		ContributionSet{Touchpoints: map[Touchpoint]struct{}{a: struct{}{}, b: struct{}{}}},
+	}
+	// both values exceed the float64 range
+	contributions[0].Value.SetString("1e400")
+	contributions[1].Value.SetString("2e400")
+
+	shapleyValues := GetShapleyValues(contributions)
+	estimates := GetApproximateShapleyValues(contributions, ShapleySamplingOptions{Samples: 2000, Seed: 1})
+	for _, touchpoint := range []Touchpoint{a, b} {
+		estimate := estimates[touchpoint]
+		shapleyValue := shapleyValues[touchpoint]
+		difference := new(big.Float).Sub(&estimate.Value, &shapleyValue)
+		if difference.Abs(difference).Cmp(new(big.Float).Mul(&estimate.StandardError, big.NewFloat(5))) > 0 {
+			t.Errorf("%s: got %s ± %s want %s", touchpoint, estimate.Value.String(), estimate.StandardError.String(), shapleyValue.String())
+		}
+	}
+}
+
+func TestGetApproximateShapleyValuesTargetStandardError(t *testing.T) {
+	contributions := contributionSetFixture()
+	estimates := GetApproximateShapleyValues(contributions, ShapleySamplingOptions{
+		Samples:             100000,
+		TargetStandardError: 5.,
+		Seed:                1,
+	})
+
+	for touchpoint, estimate := range estimates {
+		standardError, _ := estimate.StandardError.Float64()
+		if standardError > 5. {
+			t.Errorf("%s: standard error %f exceeds target", touchpoint, standardError)
+		}
+		if estimate.Samples >= 100000 {
+			t.Errorf("%s: sampling didn't stop early", touchpoint)
+		}
+	}
+}
+
+func TestGetApproximateShapleyValuesWith(t *testing.T) {
+	a, b, c := Touchpoint{"a"}, Touchpoint{"b"}, Touchpoint{"c"}
+	var gloveGame CoalitionValues
+	gloveGame.Set(map[Touchpoint]struct{}{a: struct{}{}, b: struct{}{}}, *big.NewFloat(10))
+	gloveGame.Set(map[Touchpoint]struct{}{a: struct{}{}, c: struct{}{}}, *big.NewFloat(10))
+	gloveGame.Set(map[Touchpoint]struct{}{a: struct{}{}, b: struct{}{}, c: struct{}{}}, *big.NewFloat(12))
+
+	for _, function := range []CharacteristicFunction{IntersectingValue(contributionSetFixture()), &gloveGame} {
+		shapleyValues := GetShapleyValuesWith(function)
+		estimates := GetApproximateShapleyValuesWith(function, ShapleySamplingOptions{Samples: 2000, Seed: 1})
+		if len(estimates) != len(shapleyValues) {
+			t.Errorf("got %d estimates want %d", len(estimates), len(shapleyValues))
+		}
+
+		for touchpoint, shapleyValue := range shapleyValues {
+			estimate := estimates[touchpoint]
+			got, _ := estimate.Value.Float64()
+			want, _ := shapleyValue.Float64()
+			standardError, _ := estimate.StandardError.Float64()
+
+			if math.Abs(got-want) > 5*standardError+1e-9 {
+				t.Errorf("%s: got %f ± %f want %f", touchpoint, got, standardError, want)
+			}
+		}
+	}
+}
+
+func ExampleGetOrderedShapleyValues() {
+	contributions := []Contribution{
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Search"},
+				Touchpoint{"Display"},
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+		Contribution{
+			Touchpoints: []Touchpoint{
+				Touchpoint{"Display"},
+				Touchpoint{"Search"},
+				Touchpoint{"Display"},
+			},
+			Value: *new(big.Float).SetFloat64(200.),
+		},
+	}
+	orderedShapleyValues := GetOrderedShapleyValues(contributions)
+
+	fmt.Println(orderedShapleyValues[Touchpoint{"Search"}])
+	fmt.Println(orderedShapleyValues[Touchpoint{"Display"}])
+	// Output:
+	// {150 [50 100 0]}
+	// {150 [50 50 50]}
+}
+
+func TestGetOrderedShapleyValues(t *testing.T) {
+	contributions := contributionFixture()
+	var contributionSets []ContributionSet
+	for _, contribution := range contributions {
+		contributionSets = append(contributionSets, contribution.Set())
+	}
+	orderedShapleyValues := GetOrderedShapleyValues(contributions)
+
+	for _, touchpoint := range GetAllTouchpoints(contributionSets) {
+		orderedShapleyValue := orderedShapleyValues[touchpoint]
+		expectedValue := GetShapleyValue(touchpoint, contributionSets)
+
+		got, _ := orderedShapleyValue.Value.Float64()
+		want, _ := expectedValue.Float64()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: got %f want %f", touchpoint, got, want)
+		}
+
+		for _, positions := range [][]big.Float{orderedShapleyValue.Positions, orderedShapleyValue.PositionsFromEnd} {
+			positionSum := new(big.Float)
+			for index := range positions {
+				positionSum.Add(positionSum, &positions[index])
+			}
+			got, _ = positionSum.Float64()
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("%s: positions sum to %f want %f", touchpoint, got, want)
+			}
+		}
+	}
+}
+
+func ExampleGetShapleyValueChecked() {
+	contributions := []ContributionSet{
+		ContributionSet{
+			Touchpoints: map[Touchpoint]struct{}{
+				Touchpoint{"Touchpoint 1"}: struct{}{},
+			},
+			Value: *new(big.Float).SetFloat64(100.),
+		},
+	}
+	_, err := GetShapleyValueChecked(Touchpoint{"Touchpoint 2"}, contributions)
+
+	fmt.Println(errors.Is(err, ErrUnknownTouchpoint))
+	fmt.Println(err)
+	// Output:
+	// true
+	// attribution: unknown touchpoint: Touchpoint 2
+}
+
+func TestGetShapleyValueChecked(t *testing.T) {
+	contributions := contributionSetFixture()
+	touchpoint := touchpointFixture()[2]
+
+	shapleyValue, err := GetShapleyValueChecked(touchpoint, contributions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ := shapleyValue.Float64()
+	if got != 585. {
+		t.Errorf("got %f want %f", got, 585.)
+	}
+
+	if _, err := GetShapleyValueChecked(touchpoint, nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("got %v want %v", err, ErrEmptyInput)
+	}
+	if _, err := GetShapleyValueChecked(touchpointFixture()[9], contributions); !errors.Is(err, ErrUnknownTouchpoint) {
+		t.Errorf("got %v want %v", err, ErrUnknownTouchpoint)
+	}
+
+	contributions[1].Value.SetInf(false)
+	if _, err := GetShapleyValueChecked(touchpoint, contributions); !errors.Is(err, ErrNonFiniteValue) {
+		t.Errorf("got %v want %v", err, ErrNonFiniteValue)
+	}
+	if _, err := GetShapleyValuesChecked(contributions); !errors.Is(err, ErrNonFiniteValue) {
+		t.Errorf("got %v want %v", err, ErrNonFiniteValue)
+	}
+}
+
+func TestGetShapleyValueUnknownTouchpoint(t *testing.T) {
+	shapleyValue := GetShapleyValue(touchpointFixture()[9], contributionSetFixture())
+
+	if shapleyValue.Sign() != 0 {
+		t.Errorf("got %s want 0", shapleyValue.String())
+	}
+}
+
+func TestGetApproximateShapleyValuesChecked(t *testing.T) {
+	contributions := contributionSetFixture()
+	contributions[1].Value.SetMantExp(big.NewFloat(1.), 2000)
+
+	if _, err := GetApproximateShapleyValuesChecked(contributions, ShapleySamplingOptions{}); !errors.Is(err, ErrNonFiniteValue) {
+		t.Errorf("got %v want %v", err, ErrNonFiniteValue)
+	}
+
+	// every value is finite on its own, but not their sum
+	contributions = contributionSetFixture()[:3]
+	contributions[0].Value.SetFloat64(1e308)
+	contributions[1].Value.SetFloat64(1e308)
+	contributions[2].Value.SetFloat64(-1e308)
+	if _, err := GetApproximateShapleyValuesChecked(contributions, ShapleySamplingOptions{}); !errors.Is(err, ErrNonFiniteValue) {
+		t.Errorf("got %v want %v", err, ErrNonFiniteValue)
+	}
+}
+
 // Convert an ordered Contribution into an unordered ContributionSet.
-func ExampleSet() {
+func ExampleContribution_Set() {
 	contribution := Contribution{
 		Touchpoints: Touchpoints([]Touchpoint{
 			Touchpoint{"Touchpoint 2"},